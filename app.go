@@ -1,20 +1,31 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"net/url"
+	"sort"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 	"unsafe"
 
 	"github.com/gorilla/websocket"
+	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"rez/internal/champselect"
+	"rez/internal/mockreplay"
+	"rez/internal/msgpack"
 )
 
 var (
@@ -30,6 +41,9 @@ var (
 	procAttachThreadInput        = user32.NewProc("AttachThreadInput")
 	procSetWindowLong            = user32.NewProc("SetWindowLongPtrW")
 	procGetWindowLong            = user32.NewProc("GetWindowLongPtrW")
+	procMonitorFromWindow        = user32.NewProc("MonitorFromWindow")
+	procGetMonitorInfoW          = user32.NewProc("GetMonitorInfoW")
+	procEnumDisplayMonitors      = user32.NewProc("EnumDisplayMonitors")
 )
 
 const (
@@ -43,8 +57,72 @@ const (
 )
 
 const GWL_EXSTYLE = ^uintptr(19) // -20 in two's complement
+const MONITOR_DEFAULTTONEAREST = 0x00000002
 const overlayWidth = 400
 
+// foregroundDebounce is how long League's foreground state must hold before the
+// overlay is shown or hidden, so rapid alt-tabbing doesn't make it flicker
+const foregroundDebounce = 100 * time.Millisecond
+
+const (
+	windowHandleAttempts   = 10
+	windowHandleRetryDelay = 250 * time.Millisecond
+)
+
+// Dock sides for MonitorOptions.DockSide
+const (
+	DockLeft  = "Left"
+	DockRight = "Right"
+)
+
+// MonitorOptions controls where the overlay docks relative to the League window
+type MonitorOptions struct {
+	DockSide   string `json:"dockSide"`
+	Width      int    `json:"width"`
+	Gap        int    `json:"gap"`
+	Fullscreen string `json:"fullscreen"` // What to do when League covers its whole monitor
+}
+
+// Fullscreen behaviours for MonitorOptions.Fullscreen
+const (
+	FullscreenCorner = "Corner" // Float in the top-right corner of the monitor, above League
+	FullscreenHide   = "Hide"
+)
+
+// ZOrderMode controls where the overlay sits in the window stack
+type ZOrderMode string
+
+const (
+	// ZOrderBehindLeague keeps the overlay directly behind the League window to avoid focus stealing
+	ZOrderBehindLeague ZOrderMode = "BehindLeague"
+	// ZOrderTopMost keeps the overlay above all non-topmost windows
+	ZOrderTopMost ZOrderMode = "TopMost"
+	// ZOrderNormal leaves the overlay in the regular window stack
+	ZOrderNormal ZOrderMode = "Normal"
+)
+
+// recentEventLimit caps how many event summaries GetRecentEvents can return
+const recentEventLimit = 200
+
+// EventSummary describes one champ-select event for the in-app debug log
+type EventSummary struct {
+	Timestamp string `json:"timestamp"`
+	Type      string `json:"type"`
+	Phase     string `json:"phase"`
+	Summary   string `json:"summary"`
+}
+
+// Corner pins the overlay to a corner of League's monitor instead of docking beside the window
+type Corner string
+
+const (
+	CornerNone        Corner = "" // Dock beside League as usual
+	CornerTopLeft     Corner = "TopLeft"
+	CornerTopRight    Corner = "TopRight"
+	CornerBottomLeft  Corner = "BottomLeft"
+	CornerBottomRight Corner = "BottomRight"
+)
+
 type RECT struct {
 	Left   int32
 	Top    int32
@@ -52,23 +130,52 @@ type RECT struct {
 	Bottom int32
 }
 
+// MONITORINFO mirrors the Win32 struct filled by GetMonitorInfoW
+type MONITORINFO struct {
+	CbSize    uint32
+	RcMonitor RECT
+	RcWork    RECT
+	DwFlags   uint32
+}
+
 // App struct
 type App struct {
 	ctx         context.Context
-	monitoring  bool
-	stopChan    chan bool
-	connector   *LCUConnector
+	monitoring  bool          // guarded by mu
+	stopChan    chan bool     // guarded by mu
+	connector   *LCUConnector // guarded by mu
 	lcuClient   *http.Client
-	connInfo    *ConnectionInfo
-	regionInfo  map[string]interface{}
-	mockEnabled bool
-	mockWS      string
-	mockStop    chan struct{}
-	mockConn    *websocket.Conn
+	connInfo    *ConnectionInfo        // guarded by mu
+	regionInfo  map[string]interface{} // guarded by mu
+	mockEnabled bool                   // guarded by mu
+	mockWS      string                 // guarded by mu
+	mockStop    chan struct{}          // guarded by mu
+	mockConn    *websocket.Conn        // guarded by mu
+	mockEnc     string                 // "msgpack" asks the mock server for binary frames
+	recordTo    string
+	watchMode   WatchMode
+	keepTaskbar bool     // skip the tool-window style so the overlay stays in the taskbar and alt-tab
+	companions  []string // lowercased title fragments of windows that keep the overlay shown, guarded by mu
+	mu          sync.Mutex
+	modeMu      sync.Mutex // serializes SetMode transitions
+	zOrderMode  ZOrderMode
+	cornerMode  Corner        // guarded by mu
+	offset      OverlayOffset // manual nudge from dragging, guarded by mu
+	offsetSaver *debouncedSaver
+	monitorOpts MonitorOptions
+	assetCache  map[string]string // data URLs by endpoint for the current client session, guarded by mu
+
+	recentEvents     []EventSummary         // ring buffer, guarded by mu
+	recentEventsNext int                    // oldest entry once the buffer is full
+	lastMockEventAt  time.Time              // guarded by mu
+	champSelect      map[string]interface{} // last emitted session, guarded by mu
+	spectating       bool                   // whether champSelect is spectated, guarded by mu
+	clockOffset      time.Duration          // client clock minus ours at the last timed event, guarded by mu
+	recorder         *captureRecorder       // tees live events to recordTo, guarded by mu
 }
 
 // NewApp creates a new App application struct
-func NewApp(mockEnabled bool, mockWS string) *App {
+func NewApp(mockEnabled bool, mockWS string, mockEnc string, recordTo string, watchMode WatchMode, showInTaskbar bool, monitorOpts MonitorOptions) *App {
 	// Create HTTP client that ignores SSL verification (LCU uses self-signed cert)
 	httpClient := &http.Client{
 		Transport: &http.Transport{
@@ -77,13 +184,59 @@ func NewApp(mockEnabled bool, mockWS string) *App {
 		Timeout: 10 * time.Second,
 	}
 
-	return &App{
+	monitorOpts, err := normalizeMonitorOptions(monitorOpts)
+	if err != nil {
+		log.Printf("invalid overlay positioning (%v); using defaults", err)
+		monitorOpts = MonitorOptions{DockSide: DockLeft, Width: overlayWidth}
+	}
+
+	offset, err := loadOverlayOffset()
+	if err != nil {
+		log.Printf("ignoring saved overlay offset: %v", err)
+	}
+
+	a := &App{
 		stopChan:    make(chan bool),
 		mockStop:    make(chan struct{}),
 		lcuClient:   httpClient,
 		mockEnabled: mockEnabled,
 		mockWS:      mockWS,
+		mockEnc:     mockEnc,
+		recordTo:    recordTo,
+		watchMode:   watchMode,
+		keepTaskbar: showInTaskbar,
+		zOrderMode:  ZOrderBehindLeague,
+		monitorOpts: monitorOpts,
+		assetCache:  make(map[string]string),
+		offset:      offset,
 	}
+	a.offsetSaver = newDebouncedSaver(overlayOffsetSaveDelay, a.saveOverlayOffset)
+	return a
+}
+
+// shutdown writes anything still waiting on a debounce, and any recording in
+// progress, before the app exits
+func (a *App) shutdown(ctx context.Context) {
+	a.offsetSaver.flush()
+
+	a.mu.Lock()
+	recorder := a.recorder
+	a.recorder = nil
+	a.mu.Unlock()
+	if recorder != nil {
+		recorder.close()
+	}
+}
+
+// onSecondInstanceLaunch runs in the first instance when rez is launched again.
+// The second process exits, so bring the existing overlay back into view instead.
+func (a *App) onSecondInstanceLaunch(data options.SecondInstanceData) {
+	log.Printf("another rez instance was launched (args %v); keeping this one", data.Args)
+	if a.ctx == nil {
+		return
+	}
+	runtime.WindowUnminimise(a.ctx)
+	log.Println(a.PositionWindow())
 }
 
 // startup is called when the app starts. The context is saved
@@ -93,10 +246,15 @@ func (a *App) startup(ctx context.Context) {
 
 	// Get our window handle and modify its extended styles to prevent taskbar blinking
 	go func() {
-		// Wait a bit for the window to be created
-		time.Sleep(500 * time.Millisecond)
-
-		ourHwnd := getOurWindowHandle()
+		if a.keepTaskbar {
+			log.Println("REZ_SHOW_IN_TASKBAR set; leaving window styles alone")
+			return
+		}
+		// The window may take a while to be created, so retry before giving up
+		ourHwnd := findOurWindowHandle(windowHandleAttempts, windowHandleRetryDelay)
+		if ourHwnd == 0 {
+			log.Printf("could not find overlay window after %d attempts; taskbar hiding disabled", windowHandleAttempts)
+		}
 		if ourHwnd != 0 {
 			// Get current extended style
 			exStyle, _, _ := procGetWindowLong.Call(ourHwnd, GWL_EXSTYLE)
@@ -114,31 +272,131 @@ func (a *App) startup(ctx context.Context) {
 		}
 	}()
 
-	if a.mockEnabled {
+	if a.isMock() {
 		// Mock mode: connect to mock champ-select websocket instead of LCU
-		go a.startMockChampSelect()
+		a.startMockSource()
 	} else {
-		// Initialize LCU Connector
-		a.connector = New("")
-		go a.handleLCUConnection()
-		a.connector.Start()
+		a.startLiveSource()
+	}
+
+	// Monitor window position in both modes; it keeps running across SetMode
+	go a.StartMonitoring()
+}
+
+// startLiveSource creates an LCU connector and forwards its events to the frontend
+func (a *App) startLiveSource() {
+	connector := New("")
+	connector.WatchMode = a.watchMode
+
+	var recorder *captureRecorder
+	if a.recordTo != "" {
+		// Tee live events to capture files so a game can be recorded without the capture binary
+		recorder = newCaptureRecorder(a.recordTo)
+	}
+	connector.SetRawSink(func(payload []any) {
+		if raw, err := json.Marshal(payload); err == nil {
+			a.recordEvent(raw)
+		}
+		if recorder != nil {
+			recorder.record(payload)
+		}
+	})
+
+	a.mu.Lock()
+	a.connector = connector
+	a.recorder = recorder
+	a.mu.Unlock()
+
+	go a.handleLCUConnection(connector)
+	connector.Start()
+}
+
+// startMockSource connects to the mock websocket using the current stop channel
+func (a *App) startMockSource() {
+	a.mu.Lock()
+	wsURL, stop := a.mockWS, a.mockStop
+	a.mu.Unlock()
+
+	go a.startMockChampSelect(wsURL, stop)
+}
+
+// SetMode switches between the live LCU and a mock champ-select websocket at runtime.
+// wsURL is only used for mock mode; empty keeps the current mock URL.
+func (a *App) SetMode(mock bool, wsURL string) error {
+	a.modeMu.Lock()
+	defer a.modeMu.Unlock()
+
+	a.mu.Lock()
+	if wsURL == "" {
+		wsURL = a.mockWS
+	}
+	if mock == a.mockEnabled && (!mock || wsURL == a.mockWS) {
+		a.mu.Unlock()
+		return nil
+	}
+	if mock && wsURL == "" {
+		a.mu.Unlock()
+		return fmt.Errorf("mock mode needs a websocket URL")
+	}
 
-		// Start monitoring automatically on startup
-		go a.StartMonitoring()
+	oldConnector, oldMockConn, oldMockStop, oldRecorder := a.connector, a.mockConn, a.mockStop, a.recorder
+	a.connector = nil
+	a.recorder = nil
+	a.mockConn = nil
+	a.mockStop = make(chan struct{})
+	a.mockEnabled = mock
+	a.mockWS = wsURL
+	a.connInfo = nil
+	a.regionInfo = nil
+	a.lastMockEventAt = time.Time{}
+	a.champSelect = nil
+	a.spectating = false
+	a.clockOffset = 0
+	a.mu.Unlock()
+
+	// Tear down the old source before starting the new one so their events never interleave
+	close(oldMockStop)
+	if oldMockConn != nil {
+		oldMockConn.Close()
+	}
+	if oldConnector != nil {
+		oldConnector.Stop()
+	}
+	if oldRecorder != nil {
+		oldRecorder.close()
 	}
+	runtime.EventsEmit(a.ctx, "lcu:disconnected")
 
-	// Still monitor window position in mock mode
-	if a.mockEnabled {
-		go a.StartMonitoring()
+	if mock {
+		log.Println("Switched to mock mode:", wsURL)
+		a.startMockSource()
+	} else {
+		log.Println("Switched to live mode")
+		a.startLiveSource()
 	}
+	return nil
+}
+
+// GetMode reports whether the app is currently reading from a mock source
+func (a *App) GetMode() bool {
+	return a.isMock()
+}
+
+// isMock reports whether mock mode is active
+func (a *App) isMock() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.mockEnabled
 }
 
-// handleLCUConnection handles LCU connect/disconnect events
-func (a *App) handleLCUConnection() {
+// handleLCUConnection handles LCU connect/disconnect events until the connector is stopped
+func (a *App) handleLCUConnection(connector *LCUConnector) {
 	for {
 		select {
-		case info := <-a.connector.OnConnect:
-			a.connInfo = &info
+		case <-connector.stopCh:
+			return
+		case info := <-connector.OnConnect:
+			a.setConnection(&info)
 			runtime.EventsEmit(a.ctx, "lcu:connected", info)
 
 			// Fetch region info after connection
@@ -146,24 +404,37 @@ func (a *App) handleLCUConnection() {
 				// Wait a bit for LCU to be fully ready
 				time.Sleep(1 * time.Second)
 				if regionInfo, err := a.fetchRegionLocale(); err == nil {
-					a.regionInfo = regionInfo
+					a.setRegionInfo(regionInfo)
 					runtime.EventsEmit(a.ctx, "lcu:region", regionInfo)
 				}
 			}()
 
-		case <-a.connector.OnDisconnect:
+		case attempt := <-connector.OnReconnecting:
+			runtime.EventsEmit(a.ctx, "lcu:reconnecting", map[string]interface{}{
+				"attempt": attempt,
+			})
+		case info := <-connector.OnReconnected:
+			a.setConnection(&info)
+			runtime.EventsEmit(a.ctx, "lcu:reconnected", info)
+		case topic := <-connector.OnSubscribed:
+			runtime.EventsEmit(a.ctx, "lcu:subscribed", topic)
+		case err := <-connector.OnError:
+			runtime.EventsEmit(a.ctx, "lcu:error", err.Error())
+		case <-connector.OnDisconnect:
+			a.mu.Lock()
 			a.connInfo = nil
 			a.regionInfo = nil
+			a.champSelect = nil
+			a.spectating = false
+			clear(a.assetCache)
+			a.mu.Unlock()
 			runtime.EventsEmit(a.ctx, "lcu:disconnected")
-		case champSelect := <-a.connector.OnChampSelect:
+		case champSelect := <-connector.OnChampSelect:
 			if session, ended := a.extractChampSelect(champSelect); session != nil {
-				runtime.EventsEmit(a.ctx, "lcu:champ-select", session)
-				if ended {
-					runtime.EventsEmit(a.ctx, "lcu:champ-select-ended")
-				}
+				a.emitChampSelect(session, ended)
 			}
-		case <-a.connector.OnChampSelectEnded:
-			runtime.EventsEmit(a.ctx, "lcu:champ-select-ended")
+		case <-connector.OnChampSelectEnded:
+			a.emitChampSelectEnded()
 		}
 	}
 }
@@ -205,6 +476,54 @@ func isWindowMinimized(hwnd uintptr) bool {
 	return ret != 0
 }
 
+// getMonitorInfo returns the bounds and work area of the monitor a window is on
+func getMonitorInfo(hwnd uintptr) (*MONITORINFO, error) {
+	monitor, _, _ := procMonitorFromWindow.Call(hwnd, MONITOR_DEFAULTTONEAREST)
+	if monitor == 0 {
+		return nil, fmt.Errorf("failed to find monitor")
+	}
+
+	info := MONITORINFO{CbSize: uint32(unsafe.Sizeof(MONITORINFO{}))}
+	ret, _, _ := procGetMonitorInfoW.Call(monitor, uintptr(unsafe.Pointer(&info)))
+	if ret == 0 {
+		return nil, fmt.Errorf("failed to get monitor info")
+	}
+	return &info, nil
+}
+
+// Windows caps how many callbacks a process can create, so the
+// EnumDisplayMonitors callback is made once and collects into workAreas
+var (
+	workAreasMu      sync.Mutex
+	workAreas        []RECT // guarded by workAreasMu while enumerating
+	enumMonitorsProc = syscall.NewCallback(collectWorkArea)
+)
+
+func collectWorkArea(monitor, hdc, clip, data uintptr) uintptr {
+	info := MONITORINFO{CbSize: uint32(unsafe.Sizeof(MONITORINFO{}))}
+	if ret, _, _ := procGetMonitorInfoW.Call(monitor, uintptr(unsafe.Pointer(&info))); ret != 0 {
+		workAreas = append(workAreas, info.RcWork)
+	}
+	return 1 // keep enumerating
+}
+
+// getWorkAreas returns the work area of every monitor in global coordinates.
+// Monitors left of or above the primary one have negative coordinates.
+func getWorkAreas() []RECT {
+	workAreasMu.Lock()
+	defer workAreasMu.Unlock()
+	workAreas = nil
+	procEnumDisplayMonitors.Call(0, 0, enumMonitorsProc, 0)
+	return workAreas
+}
+
+// isFullscreenRect reports whether a window rect covers the whole monitor,
+// as it does when League runs borderless or fullscreen
+func isFullscreenRect(rect *RECT, monitor *MONITORINFO) bool {
+	m := monitor.RcMonitor
+	return rect.Left <= m.Left && rect.Top <= m.Top && rect.Right >= m.Right && rect.Bottom >= m.Bottom
+}
+
 // getForegroundWindow gets the currently focused window
 func getForegroundWindow() uintptr {
 	hwnd, _, _ := procGetForegroundWindow.Call()
@@ -218,6 +537,17 @@ func getOurWindowHandle() uintptr {
 	return hwnd
 }
 
+// findOurWindowHandle polls for our window handle, waiting delay between attempts
+func findOurWindowHandle(attempts int, delay time.Duration) uintptr {
+	for i := 0; i < attempts; i++ {
+		time.Sleep(delay)
+		if hwnd := getOurWindowHandle(); hwnd != 0 {
+			return hwnd
+		}
+	}
+	return 0
+}
+
 // setWindowPos sets the window position with z-order control
 func setWindowPos(hwnd, hwndInsertAfter uintptr, x, y, width, height int, flags uint32) bool {
 	ret, _, _ := procSetWindowPos.Call(
@@ -239,6 +569,40 @@ func isLoLInForeground(lolHwnd uintptr) bool {
 	return foregroundHwnd == lolHwnd
 }
 
+// getWindowTitle returns the title bar text of hwnd
+func getWindowTitle(hwnd uintptr) string {
+	buf := make([]uint16, 256)
+	n, _, _ := procGetWindowTextW.Call(hwnd, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+	return syscall.UTF16ToString(buf[:n])
+}
+
+// isCompanionInForeground reports whether the foreground window is the overlay
+// itself or one of the allowed companion windows, which count as still being in League
+func (a *App) isCompanionInForeground() bool {
+	foregroundHwnd := getForegroundWindow()
+	if foregroundHwnd == 0 {
+		return false
+	}
+	if foregroundHwnd == getOurWindowHandle() {
+		return true
+	}
+
+	a.mu.Lock()
+	companions := a.companions
+	a.mu.Unlock()
+	if len(companions) == 0 {
+		return false
+	}
+
+	title := strings.ToLower(getWindowTitle(foregroundHwnd))
+	for _, fragment := range companions {
+		if strings.Contains(title, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
 // PositionWindow positions the app window next to the League client
 func (a *App) PositionWindow() string {
 	hwnd, err := findLeagueWindow()
@@ -257,16 +621,17 @@ func (a *App) PositionWindow() string {
 		return "LoL window is hidden or minimized"
 	}
 
-	// Calculate position (300px to the left of LoL window)
-	width := overlayWidth
-	height := int(rect.Bottom - rect.Top)
-	x := int(rect.Left) - width
-	y := int(rect.Top)
-
-	// If positioning would go off-screen to the left, position to the right instead
-	if x < 0 {
-		x = int(rect.Right)
+	opts := a.monitorOptions()
+	corner := a.GetCornerMode()
+	monitor, err := getMonitorInfo(hwnd)
+	if err != nil {
+		monitor = nil
+	}
+	if monitor != nil && isFullscreenRect(rect, monitor) && opts.Fullscreen == FullscreenHide && corner == CornerNone {
+		runtime.Hide(a.ctx)
+		return "LoL window is fullscreen"
 	}
+	x, y, width, height := a.placement(rect, monitor, opts, corner)
 
 	// Show window if it was hidden
 	runtime.Show(a.ctx)
@@ -280,24 +645,35 @@ func (a *App) PositionWindow() string {
 
 // StartMonitoring starts monitoring the League window position
 func (a *App) StartMonitoring() string {
+	a.mu.Lock()
 	if a.monitoring {
+		a.mu.Unlock()
 		return "Already monitoring"
 	}
 
 	a.monitoring = true
-	a.stopChan = make(chan bool)
+	stopChan := make(chan bool)
+	a.stopChan = stopChan
+	a.mu.Unlock()
 
 	go func() {
 		ticker := time.NewTicker(16 * time.Millisecond) // Check every ~16ms (60fps)
 		defer ticker.Stop()
 
 		var lastRect *RECT
+		var lastInsertAfter uintptr
+		var lastOpts MonitorOptions
+		var lastCorner Corner
+		var lastOffset OverlayOffset
 		var wasVisible bool = true
 		var wasInForeground bool = true
+		var hiddenForFullscreen bool
+		var pendingForeground bool = true
+		var pendingSince time.Time
 
 		for {
 			select {
-			case <-a.stopChan:
+			case <-stopChan:
 				return
 			case <-ticker.C:
 				lolHwnd, err := findLeagueWindow()
@@ -308,14 +684,21 @@ func (a *App) StartMonitoring() string {
 						wasVisible = false
 						wasInForeground = false
 					}
+					pendingForeground = false
 					continue
 				}
 
-				// Check if LoL is actually in the foreground (and not minimized)
-				inForeground := isLoLInForeground(lolHwnd) && !isWindowMinimized(lolHwnd)
+				// Check if LoL (or a companion window) is actually in the foreground and LoL isn't minimized
+				inForeground := (isLoLInForeground(lolHwnd) || a.isCompanionInForeground()) && !isWindowMinimized(lolHwnd)
+
+				// Only act on a foreground change once it has held for foregroundDebounce
+				if inForeground != pendingForeground {
+					pendingForeground = inForeground
+					pendingSince = time.Now()
+				}
 
 				// Handle foreground state changes - this is the primary visibility control
-				if inForeground != wasInForeground {
+				if inForeground != wasInForeground && time.Since(pendingSince) >= foregroundDebounce {
 					if inForeground {
 						// LoL came to foreground, show our window
 						runtime.Show(a.ctx)
@@ -328,8 +711,8 @@ func (a *App) StartMonitoring() string {
 					wasInForeground = inForeground
 				}
 
-				// If LoL is not in foreground, skip positioning
-				if !inForeground {
+				// If LoL is not in foreground (or hasn't settled there yet), skip positioning
+				if !inForeground || !wasInForeground {
 					continue
 				}
 
@@ -339,31 +722,55 @@ func (a *App) StartMonitoring() string {
 					continue
 				}
 
-				// If position or size changed, reposition our window
+				insertAfter := a.insertAfter(lolHwnd)
+				opts := a.monitorOptions()
+
+				// Borderless/fullscreen League leaves no room to dock beside it
+				monitor, err := getMonitorInfo(lolHwnd)
+				if err != nil {
+					monitor = nil
+				}
+				fullscreen := monitor != nil && isFullscreenRect(rect, monitor)
+				corner := a.GetCornerMode()
+				offset := a.GetOverlayOffset()
+				if fullscreen && opts.Fullscreen == FullscreenHide && corner == CornerNone {
+					// Re-check visibility each tick; regaining foreground shows us again
+					if wasVisible {
+						runtime.Hide(a.ctx)
+						wasVisible = false
+					}
+					hiddenForFullscreen = true
+					lastRect = nil
+					continue
+				}
+				if hiddenForFullscreen {
+					runtime.Show(a.ctx)
+					wasVisible = true
+					hiddenForFullscreen = false
+				}
+				if fullscreen {
+					// Anything below a fullscreen window is invisible
+					insertAfter = HWND_TOPMOST
+				}
+
+				// If position, size, z-order mode or settings changed, reposition our window
 				positionChanged := lastRect == nil ||
 					lastRect.Left != rect.Left ||
 					lastRect.Top != rect.Top ||
 					lastRect.Right != rect.Right ||
-					lastRect.Bottom != rect.Bottom
+					lastRect.Bottom != rect.Bottom ||
+					insertAfter != lastInsertAfter ||
+					opts != lastOpts ||
+					corner != lastCorner ||
+					offset != lastOffset
 
 				if positionChanged {
-					width := overlayWidth
-					height := int(rect.Bottom - rect.Top)
-
-					// Calculate position to the left of LoL window
-					x := int(rect.Left) - width
-					y := int(rect.Top)
-
-					// If positioning would go off-screen to the left, position to the right instead
-					if x < 0 {
-						x = int(rect.Right)
-					}
+					x, y, width, height := a.placement(rect, monitor, opts, corner)
 
 					// Use SetWindowPos for smoother, more direct positioning
 					ourHwnd := getOurWindowHandle()
 					if ourHwnd != 0 {
-						// Position right behind the LoL window (not topmost, to avoid focus stealing)
-						setWindowPos(ourHwnd, lolHwnd, x, y, width, height, SWP_NOACTIVATE)
+						setWindowPos(ourHwnd, insertAfter, x, y, width, height, SWP_NOACTIVATE)
 					} else {
 						// Fallback to runtime methods if we can't get our window handle
 						runtime.WindowSetPosition(a.ctx, x, y)
@@ -371,6 +778,10 @@ func (a *App) StartMonitoring() string {
 					}
 
 					lastRect = rect
+					lastInsertAfter = insertAfter
+					lastOpts = opts
+					lastCorner = corner
+					lastOffset = offset
 				}
 			}
 		}
@@ -379,8 +790,248 @@ func (a *App) StartMonitoring() string {
 	return "Monitoring started"
 }
 
+// placement picks the overlay rect: a pinned corner if one is set, the top-right
+// corner when League is fullscreen, otherwise docked beside League. monitor may
+// be nil if it could not be queried, in which case docking is used.
+func (a *App) placement(rect *RECT, monitor *MONITORINFO, opts MonitorOptions, corner Corner) (x, y, width, height int) {
+	switch {
+	case monitor != nil && corner != CornerNone:
+		return cornerPlacement(monitor, corner, opts, int(rect.Bottom-rect.Top))
+	case monitor != nil && isFullscreenRect(rect, monitor):
+		// There is no room beside a window that covers the screen
+		return cornerPlacement(monitor, CornerTopRight, opts, 0)
+	}
+	x, y, width, height = overlayPlacement(rect, opts, getWorkAreas())
+	offset := a.GetOverlayOffset()
+	return x + offset.X, y + offset.Y, width, height
+}
+
+// cornerPlacement puts the overlay in a corner of the monitor's work area,
+// capped to the given height
+func cornerPlacement(monitor *MONITORINFO, corner Corner, opts MonitorOptions, height int) (x, y, w, h int) {
+	work := monitor.RcWork
+	w = opts.Width
+	h = height
+	if maxHeight := int(work.Bottom-work.Top) - 2*opts.Gap; h <= 0 || h > maxHeight {
+		h = maxHeight
+	}
+
+	x = int(work.Left) + opts.Gap
+	if corner == CornerTopRight || corner == CornerBottomRight {
+		x = int(work.Right) - w - opts.Gap
+	}
+	y = int(work.Top) + opts.Gap
+	if corner == CornerBottomLeft || corner == CornerBottomRight {
+		y = int(work.Bottom) - h - opts.Gap
+	}
+	return
+}
+
+// overlayPlacement computes the overlay rect for the given League window rect.
+// workAreas are the monitors' work areas, used to tell whether docking on the
+// left would put the overlay off-screen.
+func overlayPlacement(rect *RECT, opts MonitorOptions, workAreas []RECT) (x, y, width, height int) {
+	width = opts.Width
+	height = int(rect.Bottom - rect.Top)
+	y = int(rect.Top)
+
+	if opts.DockSide == DockRight {
+		x = int(rect.Right) + opts.Gap
+		return
+	}
+
+	// Position to the left of LoL window
+	x = int(rect.Left) - width - opts.Gap
+
+	// If positioning would go off-screen to the left, position to the right instead.
+	// Negative x is fine on a monitor left of the primary one.
+	if !onScreen(x, y, height, workAreas) {
+		x = int(rect.Right) + opts.Gap
+	}
+	return
+}
+
+// onScreen reports whether an overlay whose left edge is at x, spanning y to
+// y+height, starts inside some monitor's work area. Without monitor info it
+// falls back to treating the primary monitor's left edge (x = 0) as the limit.
+func onScreen(x, y, height int, workAreas []RECT) bool {
+	if len(workAreas) == 0 {
+		return x >= 0
+	}
+	for _, area := range workAreas {
+		if x >= int(area.Left) && x < int(area.Right) && y < int(area.Bottom) && y+height > int(area.Top) {
+			return true
+		}
+	}
+	return false
+}
+
+// monitorOptions returns a copy of the current positioning settings
+func (a *App) monitorOptions() MonitorOptions {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.monitorOpts
+}
+
+// ReconfigureMonitoring updates the positioning settings in place. A running
+// monitor picks them up and repositions on its next tick without restarting.
+func (a *App) ReconfigureMonitoring(opts MonitorOptions) error {
+	opts, err := normalizeMonitorOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.monitorOpts = opts
+	a.mu.Unlock()
+	return nil
+}
+
+// normalizeMonitorOptions fills in defaults for unset fields and rejects invalid ones
+func normalizeMonitorOptions(opts MonitorOptions) (MonitorOptions, error) {
+	switch strings.ToLower(opts.DockSide) {
+	case "", "left":
+		opts.DockSide = DockLeft
+	case "right":
+		opts.DockSide = DockRight
+	default:
+		return opts, fmt.Errorf("unknown dock side %q", opts.DockSide)
+	}
+	if opts.Width <= 0 {
+		opts.Width = overlayWidth
+	}
+	if opts.Gap < 0 {
+		return opts, fmt.Errorf("gap must not be negative")
+	}
+	switch strings.ToLower(opts.Fullscreen) {
+	case "", "corner":
+		opts.Fullscreen = FullscreenCorner
+	case "hide":
+		opts.Fullscreen = FullscreenHide
+	default:
+		return opts, fmt.Errorf("unknown fullscreen mode %q", opts.Fullscreen)
+	}
+	return opts, nil
+}
+
+// GetMonitorOptions returns the current positioning settings
+func (a *App) GetMonitorOptions() MonitorOptions {
+	return a.monitorOptions()
+}
+
+// SetOverlayOffset moves the docked overlay by x, y pixels from where it would
+// normally sit. The frontend calls it while the overlay is dragged; the offset
+// is saved to disk once the drag has paused for a second, not on every move.
+func (a *App) SetOverlayOffset(x, y int) {
+	a.mu.Lock()
+	a.offset = OverlayOffset{X: x, Y: y}
+	a.mu.Unlock()
+	a.offsetSaver.trigger()
+}
+
+// GetOverlayOffset returns the manual offset applied to the docked overlay
+func (a *App) GetOverlayOffset() OverlayOffset {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.offset
+}
+
+// saveOverlayOffset persists the current offset; offsetSaver calls it
+func (a *App) saveOverlayOffset() {
+	if err := saveOverlayOffset(a.GetOverlayOffset()); err != nil {
+		log.Printf("failed to save overlay offset: %v", err)
+	}
+}
+
+// SetZOrderMode changes how the overlay is stacked relative to other windows
+func (a *App) SetZOrderMode(mode string) error {
+	switch ZOrderMode(mode) {
+	case ZOrderBehindLeague, ZOrderTopMost, ZOrderNormal:
+	default:
+		return fmt.Errorf("unknown z-order mode %q", mode)
+	}
+
+	a.mu.Lock()
+	a.zOrderMode = ZOrderMode(mode)
+	a.mu.Unlock()
+	return nil
+}
+
+// GetZOrderMode returns the current z-order mode
+func (a *App) GetZOrderMode() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return string(a.zOrderMode)
+}
+
+// SetCornerMode pins the overlay to a corner of the monitor League is on,
+// ignoring League's own rect. CornerNone goes back to docking beside League.
+func (a *App) SetCornerMode(corner Corner) error {
+	switch corner {
+	case CornerNone, CornerTopLeft, CornerTopRight, CornerBottomLeft, CornerBottomRight:
+	default:
+		return fmt.Errorf("unknown corner %q", corner)
+	}
+
+	a.mu.Lock()
+	a.cornerMode = corner
+	a.mu.Unlock()
+	return nil
+}
+
+// GetCornerMode returns the pinned corner, or an empty string when docking beside League
+func (a *App) GetCornerMode() Corner {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.cornerMode
+}
+
+// SetCompanionWindows sets the windows that keep the overlay shown while focused,
+// matched case-insensitively against any part of their title (e.g. a build guide).
+// The overlay itself always counts.
+func (a *App) SetCompanionWindows(titles []string) {
+	var companions []string
+	for _, title := range titles {
+		if title = strings.ToLower(strings.TrimSpace(title)); title != "" {
+			companions = append(companions, title)
+		}
+	}
+
+	a.mu.Lock()
+	a.companions = companions
+	a.mu.Unlock()
+}
+
+// GetCompanionWindows returns the title fragments set by SetCompanionWindows
+func (a *App) GetCompanionWindows() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string{}, a.companions...)
+}
+
+// insertAfter returns the hwndInsertAfter argument for SetWindowPos in the current z-order mode
+func (a *App) insertAfter(lolHwnd uintptr) uintptr {
+	a.mu.Lock()
+	mode := a.zOrderMode
+	a.mu.Unlock()
+
+	switch mode {
+	case ZOrderTopMost:
+		return HWND_TOPMOST
+	case ZOrderNormal:
+		// NOTOPMOST also clears a previous TopMost setting
+		return HWND_NOTOPMOST
+	default:
+		// Position right behind the LoL window (not topmost, to avoid focus stealing)
+		return lolHwnd
+	}
+}
+
 // StopMonitoring stops monitoring the League window
 func (a *App) StopMonitoring() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
 	if !a.monitoring {
 		return "Not currently monitoring"
 	}
@@ -395,23 +1046,75 @@ func (a *App) StopMonitoring() string {
 
 // lcuRequest makes an HTTP request to the LCU API
 func (a *App) lcuRequest(method, endpoint string) (map[string]interface{}, error) {
-	if a.mockEnabled {
+	return a.lcuRequestWithBody(method, endpoint, nil)
+}
+
+// riotClientEndpoints are served by the Riot Client on its own port. The LCU
+// proxies them too, but not on every client version.
+var riotClientEndpoints = []string{
+	"/riotclient/region-locale",
+	"/riot-client-auth/",
+	"/rso-auth/",
+}
+
+// usesRiotClient reports whether endpoint should go to the Riot Client first
+func usesRiotClient(endpoint string) bool {
+	for _, prefix := range riotClientEndpoints {
+		if strings.HasPrefix(endpoint, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// lcuRequestWithBody makes an HTTP request to the LCU API with an optional JSON body.
+// Riot Client endpoints try the Riot Client's port first and fall back to the LCU.
+func (a *App) lcuRequestWithBody(method, endpoint string, payload interface{}) (map[string]interface{}, error) {
+	if a.isMock() {
 		return a.mockLCUResponse(endpoint)
 	}
 
-	if a.connInfo == nil {
+	if usesRiotClient(endpoint) {
+		if riotClient, err := RiotClientConnection(); err == nil {
+			result, err := a.doJSONRequest(&riotClient, method, endpoint, payload)
+			if err == nil {
+				return result, nil
+			}
+			log.Printf("riot client request failed, retrying through LCU: %v", err)
+		}
+	}
+
+	connInfo := a.connection()
+	if connInfo == nil {
 		return nil, fmt.Errorf("not connected to LCU")
 	}
+	return a.doJSONRequest(connInfo, method, endpoint, payload)
+}
+
+// doJSONRequest sends a request with an optional JSON body to the client described
+// by connInfo and decodes the JSON object it returns
+func (a *App) doJSONRequest(connInfo *ConnectionInfo, method, endpoint string, payload interface{}) (map[string]interface{}, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
 
-	url := fmt.Sprintf("%s://%s:%s%s", a.connInfo.Protocol, a.connInfo.Address, a.connInfo.Port, endpoint)
-	req, err := http.NewRequest(method, url, nil)
+	url := fmt.Sprintf("%s://%s:%s%s", connInfo.Protocol, connInfo.Address, connInfo.Port, endpoint)
+	req, err := http.NewRequest(method, url, reqBody)
 	if err != nil {
 		return nil, err
 	}
 
 	// Add basic auth
-	auth := base64.StdEncoding.EncodeToString([]byte(a.connInfo.Username + ":" + a.connInfo.Password))
+	auth := base64.StdEncoding.EncodeToString([]byte(connInfo.Username + ":" + connInfo.Password))
 	req.Header.Add("Authorization", "Basic "+auth)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	resp, err := a.lcuClient.Do(req)
 	if err != nil {
@@ -424,6 +1127,15 @@ func (a *App) lcuRequest(method, endpoint string) (map[string]interface{}, error
 		return nil, err
 	}
 
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, &lcuStatusError{Method: method, Endpoint: endpoint, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	// Write endpoints frequently answer with 204 No Content
+	if len(bytes.TrimSpace(body)) == 0 {
+		return map[string]interface{}{}, nil
+	}
+
 	var result map[string]interface{}
 	if err := json.Unmarshal(body, &result); err != nil {
 		return nil, err
@@ -432,6 +1144,74 @@ func (a *App) lcuRequest(method, endpoint string) (map[string]interface{}, error
 	return result, nil
 }
 
+// lcuStatusError is returned when the client answers a request with an HTTP error status
+type lcuStatusError struct {
+	Method     string
+	Endpoint   string
+	StatusCode int
+	Status     string
+}
+
+func (e *lcuStatusError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Method, e.Endpoint, e.Status)
+}
+
+// hasLCUStatus reports whether err is an HTTP error response with one of codes
+func hasLCUStatus(err error, codes ...int) bool {
+	var statusErr *lcuStatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	for _, code := range codes {
+		if statusErr.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// lcuGetBytes fetches a binary LCU resource and returns its body and content type
+func (a *App) lcuGetBytes(endpoint string) ([]byte, string, error) {
+	if a.isMock() {
+		return nil, "", fmt.Errorf("LCU assets are not available in mock mode")
+	}
+
+	connInfo := a.connection()
+	if connInfo == nil {
+		return nil, "", fmt.Errorf("not connected to LCU")
+	}
+
+	url := fmt.Sprintf("%s://%s:%s%s", connInfo.Protocol, connInfo.Address, connInfo.Port, endpoint)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	auth := base64.StdEncoding.EncodeToString([]byte(connInfo.Username + ":" + connInfo.Password))
+	req.Header.Add("Authorization", "Basic "+auth)
+
+	resp, err := a.lcuClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, "", fmt.Errorf("GET %s: %s", endpoint, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	return body, contentType, nil
+}
+
 // GetCurrentSummoner fetches the current summoner's profile
 func (a *App) GetCurrentSummoner() (map[string]interface{}, error) {
 	return a.lcuRequest("GET", "/lol-summoner/v1/current-summoner")
@@ -452,6 +1232,93 @@ func (a *App) GetMatchHistory() (map[string]interface{}, error) {
 	return a.lcuRequest("GET", "/lol-match-history/v1/products/lol/current-summoner/matches")
 }
 
+// MatchSummary is one of the current summoner's recent games
+type MatchSummary struct {
+	GameID     int64   `json:"gameId"`
+	QueueID    int     `json:"queueId"`
+	GameMode   string  `json:"gameMode"`
+	ChampionID int     `json:"championId"`
+	Win        bool    `json:"win"`
+	Kills      int     `json:"kills"`
+	Deaths     int     `json:"deaths"`
+	Assists    int     `json:"assists"`
+	KDA        float64 `json:"kda"` // (kills + assists) / deaths, with deaths counted as at least 1
+	PlayedAt   string  `json:"playedAt"`
+	Duration   int     `json:"duration"` // seconds
+}
+
+// GetRecentMatches returns typed summaries of the current summoner's last count
+// games, newest first
+func (a *App) GetRecentMatches(count int) ([]MatchSummary, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("count must be positive")
+	}
+
+	result, err := a.lcuRequest("GET", fmt.Sprintf("/lol-match-history/v1/products/lol/current-summoner/matches?begIndex=0&endIndex=%d", count))
+	if err != nil {
+		return nil, err
+	}
+
+	// Re-encode the nested map so encoding/json can do the digging
+	body, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var history struct {
+		Games struct {
+			Games []struct {
+				GameID       int64  `json:"gameId"`
+				QueueID      int    `json:"queueId"`
+				GameMode     string `json:"gameMode"`
+				GameCreation int64  `json:"gameCreation"`
+				GameDuration int    `json:"gameDuration"`
+				Participants []struct {
+					ChampionID int `json:"championId"`
+					Stats      struct {
+						Win     bool `json:"win"`
+						Kills   int  `json:"kills"`
+						Deaths  int  `json:"deaths"`
+						Assists int  `json:"assists"`
+					} `json:"stats"`
+				} `json:"participants"`
+			} `json:"games"`
+		} `json:"games"`
+	}
+	if err := json.Unmarshal(body, &history); err != nil {
+		return nil, fmt.Errorf("failed to parse match history: %w", err)
+	}
+
+	games := history.Games.Games
+	sort.SliceStable(games, func(i, j int) bool { return games[i].GameCreation > games[j].GameCreation })
+
+	matches := make([]MatchSummary, 0, count)
+	for _, game := range games {
+		if len(matches) == count {
+			break
+		}
+		// The current summoner's history lists only their own participant
+		if len(game.Participants) == 0 {
+			continue
+		}
+		player := game.Participants[0]
+		stats := player.Stats
+		matches = append(matches, MatchSummary{
+			GameID:     game.GameID,
+			QueueID:    game.QueueID,
+			GameMode:   game.GameMode,
+			ChampionID: player.ChampionID,
+			Win:        stats.Win,
+			Kills:      stats.Kills,
+			Deaths:     stats.Deaths,
+			Assists:    stats.Assists,
+			KDA:        float64(stats.Kills+stats.Assists) / float64(max(stats.Deaths, 1)),
+			PlayedAt:   time.UnixMilli(game.GameCreation).UTC().Format(time.RFC3339),
+			Duration:   game.GameDuration,
+		})
+	}
+	return matches, nil
+}
+
 // GetFriends fetches the friends list
 func (a *App) GetFriends() ([]interface{}, error) {
 	result, err := a.lcuRequest("GET", "/lol-chat/v1/friends")
@@ -483,87 +1350,694 @@ func (a *App) GetConversations() ([]interface{}, error) {
 	return []interface{}{result}, nil
 }
 
+// SendChampSelectMessage posts text to the champ-select chat. In mock mode the
+// message is echoed back to the frontend as if the client had delivered it.
+func (a *App) SendChampSelectMessage(text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("message is empty")
+	}
+
+	if a.isMock() {
+		runtime.EventsEmit(a.ctx, "lcu:champ-select-chat", map[string]interface{}{
+			"eventType": "Create",
+			"uri":       "/lol-chat/v1/conversations/mock@champ-select.pvp.net/messages",
+			"data": map[string]interface{}{
+				"body":      text,
+				"type":      "groupchat",
+				"fromId":    "mock-puuid",
+				"timestamp": time.Now().Format(time.RFC3339Nano),
+			},
+		})
+		return nil
+	}
+
+	id, err := a.champSelectConversationID()
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("/lol-chat/v1/conversations/%s/messages", url.PathEscape(id))
+	if _, err := a.lcuRequestWithBody("POST", endpoint, map[string]interface{}{"body": text, "type": "chat"}); err != nil {
+		return fmt.Errorf("failed to send champ select message: %w", err)
+	}
+	return nil
+}
+
+// champSelectConversationID finds the champ-select chat room, preferring the id
+// the session advertises and falling back to scanning the open conversations.
+func (a *App) champSelectConversationID() (string, error) {
+	if session, err := a.lcuRequest("GET", "/lol-champ-select/v1/session"); err == nil {
+		if details, ok := session["chatDetails"].(map[string]interface{}); ok {
+			for _, key := range []string{"multiUserChatId", "chatRoomName"} {
+				if id, ok := details[key].(string); ok && id != "" {
+					return id, nil
+				}
+			}
+		}
+	}
+
+	// The conversations endpoint answers with a bare array, which lcuRequest can't decode
+	body, _, err := a.lcuGetBytes("/lol-chat/v1/conversations")
+	if err != nil {
+		return "", err
+	}
+	var conversations []map[string]interface{}
+	if err := json.Unmarshal(body, &conversations); err != nil {
+		return "", err
+	}
+	for _, conversation := range conversations {
+		if conversation["type"] == "championSelect" {
+			if id, ok := conversation["id"].(string); ok && id != "" {
+				return id, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no champ select conversation found")
+}
+
 // GetLobby fetches current lobby information
 func (a *App) GetLobby() (map[string]interface{}, error) {
 	return a.lcuRequest("GET", "/lol-lobby/v2/lobby")
 }
 
+// IsInChampSelect reports whether the client currently has an active champ select session
+func (a *App) IsInChampSelect() bool {
+	_, err := a.lcuRequest("GET", "/lol-champ-select/v1/session")
+	return err == nil
+}
+
+// DodgeChampSelect leaves the current champ select, accepting the dodge penalty
+func (a *App) DodgeChampSelect() error {
+	if !a.IsInChampSelect() {
+		return fmt.Errorf("not currently in champ select")
+	}
+
+	_, err := a.lcuRequest("POST", "/lol-lobby/v2/lobby/matchmaking/quit-dodge")
+	if err == nil {
+		return nil
+	}
+
+	// Older clients lack quit-dodge; ending the login session is the only way out.
+	// Any other failure must not log the user out.
+	if !hasLCUStatus(err, http.StatusNotFound, http.StatusMethodNotAllowed) {
+		return fmt.Errorf("failed to dodge champ select: %w", err)
+	}
+	if _, err := a.lcuRequest("DELETE", "/lol-login/v1/session"); err != nil {
+		return fmt.Errorf("failed to dodge champ select: %w", err)
+	}
+	return nil
+}
+
+// LockIn hovers and locks in championId for the local player's current pick
+func (a *App) LockIn(championId int) error {
+	return champselect.Act(a.lcuRequestWithBody, "pick", championId)
+}
+
+// BanChampion hovers and locks in championId for the local player's current ban
+func (a *App) BanChampion(championId int) error {
+	return champselect.Act(a.lcuRequestWithBody, "ban", championId)
+}
+
+// GetCurrentRunePage fetches the local player's currently selected rune page
+func (a *App) GetCurrentRunePage() (map[string]interface{}, error) {
+	return a.lcuRequest("GET", "/lol-perks/v1/currentpage")
+}
+
+// SetCurrentRunePage applies a rune page. Pages with an id update that page in place;
+// pages without one are created and then selected.
+func (a *App) SetCurrentRunePage(page map[string]interface{}) error {
+	if id, ok := numberValue(page["id"]); ok && id > 0 {
+		_, err := a.lcuRequestWithBody("PUT", fmt.Sprintf("/lol-perks/v1/pages/%d", int64(id)), page)
+		if err != nil {
+			return fmt.Errorf("failed to update rune page: %w", err)
+		}
+		_, err = a.lcuRequestWithBody("PUT", "/lol-perks/v1/currentpage", int64(id))
+		return err
+	}
+
+	created, err := a.lcuRequestWithBody("POST", "/lol-perks/v1/pages", page)
+	if err != nil {
+		return fmt.Errorf("failed to create rune page: %w", err)
+	}
+
+	// Creating a page selects it on most clients, but be explicit when we know the id
+	if id, ok := numberValue(created["id"]); ok {
+		_, err = a.lcuRequestWithBody("PUT", "/lol-perks/v1/currentpage", int64(id))
+	}
+	return err
+}
+
+// GetSummonerSpells returns the local player's selected summoner spells in champ select
+func (a *App) GetSummonerSpells() (map[string]interface{}, error) {
+	session, err := a.lcuRequest("GET", "/lol-champ-select/v1/session")
+	if err != nil {
+		return nil, err
+	}
+
+	localCell, _ := numberValue(session["localPlayerCellId"])
+	team, _ := session["myTeam"].([]interface{})
+	for _, entry := range team {
+		member, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if cell, _ := numberValue(member["cellId"]); cell != localCell {
+			continue
+		}
+		return map[string]interface{}{
+			"spell1Id": member["spell1Id"],
+			"spell2Id": member["spell2Id"],
+		}, nil
+	}
+
+	return nil, fmt.Errorf("local player not found in champ select")
+}
+
+// GetGameModeAssets resolves a queue to its game mode and map, and returns the map's
+// LCU asset paths (backgrounds, icons, sounds) so the overlay can theme itself per
+// mode. Paths can be loaded with FetchLCUAsset.
+func (a *App) GetGameModeAssets(queueId int) (map[string]interface{}, error) {
+	queue, err := a.lcuRequest("GET", fmt.Sprintf("/lol-game-queues/v1/queues/%d", queueId))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up queue %d: %w", queueId, err)
+	}
+	mapID, ok := numberValue(queue["mapId"])
+	if !ok {
+		return nil, fmt.Errorf("queue %d has no map", queueId)
+	}
+
+	gameMap, err := a.lcuRequest("GET", fmt.Sprintf("/lol-maps/v1/map/%d", int64(mapID)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up map %d: %w", int64(mapID), err)
+	}
+
+	// Map data lists asset paths without a leading slash; FetchLCUAsset wants one
+	assets := map[string]interface{}{}
+	if raw, ok := gameMap["assets"].(map[string]interface{}); ok {
+		for name, value := range raw {
+			if path, ok := value.(string); ok && path != "" {
+				assets[name] = "/" + strings.TrimPrefix(path, "/")
+			}
+		}
+	}
+
+	return map[string]interface{}{
+		"queueId":  queueId,
+		"gameMode": queue["gameMode"],
+		"mapId":    int64(mapID),
+		"mapName":  gameMap["name"],
+		"assets":   assets,
+	}, nil
+}
+
+// GetChampionIconURL returns a public URL for a champion's square icon.
+// Unlike the LCU-hosted asset it needs no auth, so the frontend can load it directly.
+func (a *App) GetChampionIconURL(championId int) string {
+	return fmt.Sprintf("https://raw.communitydragon.org/latest/plugins/rcp-be-lol-game-data/global/default/v1/champion-icons/%d.png", championId)
+}
+
+// GetChampionIcon returns a champion's icon from the LCU as a data URL
+func (a *App) GetChampionIcon(championId int) (string, error) {
+	return a.FetchLCUAsset(fmt.Sprintf("/lol-game-data/assets/v1/champion-icons/%d.png", championId))
+}
+
+// maxAssetCacheEntries bounds FetchLCUAsset's cache; a full champ select with
+// skins and summoner icons needs well under this many
+const maxAssetCacheEntries = 256
+
+// FetchLCUAsset fetches a binary LCU asset (champion icons, profile icons, etc.) and
+// returns it as a base64 data URL. The frontend can't load these directly because of
+// the self-signed cert and basic auth. Results are cached per endpoint until the
+// client disconnects or reconnects.
+func (a *App) FetchLCUAsset(endpoint string) (string, error) {
+	if !strings.HasPrefix(endpoint, "/") {
+		return "", fmt.Errorf("asset endpoint must start with /: %q", endpoint)
+	}
+
+	a.mu.Lock()
+	cached, ok := a.assetCache[endpoint]
+	a.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	body, contentType, err := a.lcuGetBytes(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	dataURL := "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(body)
+	a.mu.Lock()
+	if _, ok := a.assetCache[endpoint]; !ok && len(a.assetCache) >= maxAssetCacheEntries {
+		// Evict an arbitrary entry; map iteration order is unspecified
+		for key := range a.assetCache {
+			delete(a.assetCache, key)
+			break
+		}
+	}
+	a.assetCache[endpoint] = dataURL
+	a.mu.Unlock()
+	return dataURL, nil
+}
+
 // IsLCUConnected returns whether we're connected to the LCU
 func (a *App) IsLCUConnected() bool {
 	// if in mock mode, always return true
-	if a.mockEnabled {
+	if a.isMock() {
 		return true
 	}
-	return a.connInfo != nil
+	return a.connection() != nil
+}
+
+// LCUWebSocketURL returns the websocket URL rez is reading events from, so
+// external tools can reuse the discovered connection. In live mode it includes
+// the client's credentials; in mock mode it is the mock server URL.
+func (a *App) LCUWebSocketURL() (string, error) {
+	a.mu.Lock()
+	mock, mockWS, mockConn := a.mockEnabled, a.mockWS, a.mockConn
+	a.mu.Unlock()
+
+	if mock {
+		if mockConn == nil {
+			return "", fmt.Errorf("not connected to the mock server")
+		}
+		return withMockEncoding(mockWS, a.mockEnc), nil
+	}
+
+	info := a.connection()
+	if info == nil {
+		return "", fmt.Errorf("not connected to LCU")
+	}
+	return info.WebSocketURL(), nil
 }
 
 // GetRegionInfo returns the cached region and locale info
 func (a *App) GetRegionInfo() map[string]interface{} {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	return a.regionInfo
 }
 
+// DetectRegionHint returns a best guess at the client distribution ("Global",
+// "CN" or "Garena") from the League install directory. Unlike GetRegionInfo it
+// doesn't need the HTTP API, so it is available as soon as the install is found.
+// It returns "" when there is no install or it gives no hint.
+func (a *App) DetectRegionHint() string {
+	a.mu.Lock()
+	connector := a.connector
+	a.mu.Unlock()
+	if connector == nil {
+		return ""
+	}
+	return LCUVariant(connector.InstallDir())
+}
+
+// connection returns a copy of the current LCU connection info, or nil if disconnected
+func (a *App) connection() *ConnectionInfo {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.connInfo == nil {
+		return nil
+	}
+	info := *a.connInfo
+	return &info
+}
+
+// setConnection records a new or re-established connection. The client may
+// have restarted or patched in between, so cached assets are dropped.
+func (a *App) setConnection(info *ConnectionInfo) {
+	a.mu.Lock()
+	a.connInfo = info
+	clear(a.assetCache)
+	a.mu.Unlock()
+}
+
+// recordEvent appends a summary of a raw champ-select payload to the recent events buffer
+func (a *App) recordEvent(raw []byte) {
+	eventType, summary, phase := mockreplay.Summarize(raw)
+	event := EventSummary{
+		Timestamp: time.Now().Format(time.RFC3339Nano),
+		Type:      eventType,
+		Phase:     phase,
+		Summary:   summary,
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.recentEvents) < recentEventLimit {
+		a.recentEvents = append(a.recentEvents, event)
+		return
+	}
+	a.recentEvents[a.recentEventsNext] = event
+	a.recentEventsNext = (a.recentEventsNext + 1) % recentEventLimit
+}
+
+// LastChampSelectEventAt returns when the last champ-select event arrived from
+// the current source, or the zero time if none has
+func (a *App) LastChampSelectEventAt() time.Time {
+	a.mu.Lock()
+	connector, lastMock := a.connector, a.lastMockEventAt
+	a.mu.Unlock()
+
+	if connector != nil {
+		return connector.LastChampSelectEventAt()
+	}
+	return lastMock
+}
+
+// GetRecentEvents returns up to the last n champ-select event summaries, oldest first
+func (a *App) GetRecentEvents(n int) []EventSummary {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	total := len(a.recentEvents)
+	if n <= 0 || n > total {
+		n = total
+	}
+
+	events := make([]EventSummary, 0, n)
+	for i := total - n; i < total; i++ {
+		events = append(events, a.recentEvents[(a.recentEventsNext+i)%total])
+	}
+	return events
+}
+
+func (a *App) setRegionInfo(regionInfo map[string]interface{}) {
+	a.mu.Lock()
+	a.regionInfo = regionInfo
+	a.mu.Unlock()
+}
+
 // fetchRegionLocale fetches the client's region and locale info from LCU
 func (a *App) fetchRegionLocale() (map[string]interface{}, error) {
 	return a.lcuRequest("GET", "/riotclient/region-locale")
 }
 
-// startMockChampSelect connects to the mock websocket and forwards events to the frontend.
-func (a *App) startMockChampSelect() {
-	conn, _, err := websocket.DefaultDialer.Dial(a.mockWS, nil)
+// startMockChampSelect connects to the mock websocket and forwards events to the frontend
+// until stop is closed.
+func (a *App) startMockChampSelect(wsURL string, stop chan struct{}) {
+	conn, _, err := websocket.DefaultDialer.Dial(withMockEncoding(wsURL, a.mockEnc), nil)
 	if err != nil {
 		runtime.EventsEmit(a.ctx, "lcu:disconnected")
 		return
 	}
 
+	a.mu.Lock()
+	select {
+	case <-stop:
+		// Mode changed while dialing
+		a.mu.Unlock()
+		conn.Close()
+		return
+	default:
+	}
 	a.mockConn = conn
-	a.regionInfo = map[string]interface{}{
+	a.mu.Unlock()
+	regionInfo := map[string]interface{}{
 		"region": "OC1",
 		"locale": "en_AU",
 		"mock":   true,
 	}
+	a.setRegionInfo(regionInfo)
 	runtime.EventsEmit(a.ctx, "lcu:connected", map[string]interface{}{
 		"mode": "mock",
-		"url":  a.mockWS,
+		"url":  wsURL,
 	})
-	runtime.EventsEmit(a.ctx, "lcu:region", a.regionInfo)
+	runtime.EventsEmit(a.ctx, "lcu:region", regionInfo)
 
 	go func() {
 		defer func() {
 			conn.Close()
-			runtime.EventsEmit(a.ctx, "lcu:disconnected")
+			a.mu.Lock()
+			a.champSelect = nil
+			a.spectating = false
+			a.mu.Unlock()
+			// SetMode announces the disconnect itself when it stops us
+			select {
+			case <-stop:
+			default:
+				runtime.EventsEmit(a.ctx, "lcu:disconnected")
+			}
 		}()
 
 		for {
 			select {
-			case <-a.mockStop:
+			case <-stop:
 				return
 			default:
 			}
 
-			_, data, err := conn.ReadMessage()
+			msgType, data, err := conn.ReadMessage()
 			if err != nil {
 				return
 			}
+			if msgType == websocket.BinaryMessage {
+				// msgpack-encoded frame; everything below works on JSON
+				if data, err = msgpack.ToJSON(data); err != nil {
+					log.Printf("mock: dropping undecodable binary frame: %v", err)
+					continue
+				}
+			}
+
+			if raw, ok := a.unwrapMockFrame(data); ok {
+				data = raw
+			}
 
 			var payload []interface{}
 			if err := json.Unmarshal(data, &payload); err != nil {
 				continue
 			}
+			a.recordEvent(data)
+			a.mu.Lock()
+			a.lastMockEventAt = time.Now()
+			a.mu.Unlock()
+
+			if len(payload) >= 3 && payload[1] == chatTopic {
+				// Champ-select chat from a capture; never a session update
+				runtime.EventsEmit(a.ctx, "lcu:champ-select-chat", payload[2])
+				continue
+			}
 
 			if session, ended := a.extractChampSelect(payload); session != nil {
-				runtime.EventsEmit(a.ctx, "lcu:champ-select", session)
-				if ended {
-					runtime.EventsEmit(a.ctx, "lcu:champ-select-ended")
-				}
+				a.emitChampSelect(session, ended)
 			}
 		}
 	}()
 }
 
+// unwrapMockFrame handles frames from a mock server started with -wrap, which
+// sends {index, total, raw} instead of the bare payload. The position is emitted
+// as demo:progress so the frontend can show how far a replay has got.
+func (a *App) unwrapMockFrame(data []byte) (json.RawMessage, bool) {
+	var wrapped struct {
+		Index *int            `json:"index"`
+		Total int             `json:"total"`
+		Raw   json.RawMessage `json:"raw"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil || wrapped.Index == nil || len(wrapped.Raw) == 0 {
+		return nil, false
+	}
+
+	runtime.EventsEmit(a.ctx, "demo:progress", map[string]interface{}{
+		"current": *wrapped.Index + 1,
+		"total":   wrapped.Total,
+	})
+	return wrapped.Raw, true
+}
+
+// withMockEncoding adds the encoding query parameter the mock server uses to pick
+// a client's frame format. JSON is the server default and needs no parameter.
+func withMockEncoding(wsURL, encoding string) string {
+	if encoding == "" || strings.EqualFold(encoding, "json") {
+		return wsURL
+	}
+	u, err := url.Parse(wsURL)
+	if err != nil {
+		return wsURL
+	}
+	q := u.Query()
+	q.Set("encoding", strings.ToLower(encoding))
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// IsSpectating reports whether the current champ select is being spectated
+// rather than played, e.g. a custom game joined as a spectator.
+func (a *App) IsSpectating() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.spectating
+}
+
+// ClockOffset returns how far the League client's clock is ahead of ours (negative
+// if behind), measured from timer.internalNowInEpochMs on the last champ-select
+// event. Add it to the local time before comparing against client timestamps.
+// It is zero until a session with a timer has been seen.
+func (a *App) ClockOffset() time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.clockOffset
+}
+
+// sessionClockOffset compares the session's timer.internalNowInEpochMs with now
+func sessionClockOffset(session map[string]interface{}, now time.Time) (time.Duration, bool) {
+	timer, ok := session["timer"].(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	clientNow, ok := numberValue(timer["internalNowInEpochMs"])
+	if !ok || clientNow <= 0 {
+		return 0, false
+	}
+	return time.UnixMilli(int64(clientNow)).Sub(now), true
+}
+
+// isSpectatingSession mirrors ChampSelectSession.Spectating for a decoded session map
+func isSpectatingSession(session map[string]interface{}) bool {
+	if spectating, _ := session["isSpectating"].(bool); spectating {
+		return true
+	}
+	cell, ok := numberValue(session["localPlayerCellId"])
+	return ok && cell < 0
+}
+
+// emitChampSelect caches a session for GetCurrentChampSelect and pushes it to the frontend
+func (a *App) emitChampSelect(session map[string]interface{}, ended bool) {
+	spectating := !ended && isSpectatingSession(session)
+	offset, hasOffset := sessionClockOffset(session, time.Now())
+	a.mu.Lock()
+	if hasOffset {
+		a.clockOffset = offset
+	}
+	if ended {
+		a.champSelect = nil
+	} else {
+		a.champSelect = session
+	}
+	spectatingChanged := spectating != a.spectating
+	a.spectating = spectating
+	a.mu.Unlock()
+
+	if spectatingChanged {
+		// Spectated sessions have no local player; the overlay can hide or switch layouts
+		runtime.EventsEmit(a.ctx, "lcu:spectating", spectating)
+	}
+	runtime.EventsEmit(a.ctx, "lcu:champ-select", session)
+	if ended {
+		runtime.EventsEmit(a.ctx, "lcu:champ-select-ended")
+	}
+}
+
+// emitChampSelectEnded clears the cached session and tells the frontend champ select is over
+func (a *App) emitChampSelectEnded() {
+	a.mu.Lock()
+	a.champSelect = nil
+	wasSpectating := a.spectating
+	a.spectating = false
+	a.mu.Unlock()
+
+	if wasSpectating {
+		runtime.EventsEmit(a.ctx, "lcu:spectating", false)
+	}
+
+	runtime.EventsEmit(a.ctx, "lcu:champ-select-ended")
+}
+
+// GetCurrentChampSelect returns the last champ-select session pushed to the
+// frontend, so a reloaded page can catch up without waiting for the next event
+func (a *App) GetCurrentChampSelect() (map[string]interface{}, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.champSelect == nil {
+		return nil, fmt.Errorf("not in champ select")
+	}
+	return a.champSelect, nil
+}
+
+// GetTeamIntents returns what each teammate is hovering (championPickIntent) in the
+// current champ select, keyed by cellId. Teammates without a hover are left out.
+func (a *App) GetTeamIntents() (map[int]int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.champSelect == nil {
+		return nil, fmt.Errorf("not in champ select")
+	}
+
+	intents := make(map[int]int)
+	team, _ := a.champSelect["myTeam"].([]interface{})
+	for _, entry := range team {
+		member, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		cell, ok := numberValue(member["cellId"])
+		if !ok {
+			continue
+		}
+		if intent, ok := numberValue(member["championPickIntent"]); ok && intent > 0 {
+			intents[int(cell)] = int(intent)
+		}
+	}
+	return intents, nil
+}
+
+// currentSession decodes the last emitted champ-select session into its typed form
+func (a *App) currentSession() (ChampSelectSession, error) {
+	var session ChampSelectSession
+	a.mu.Lock()
+	if a.champSelect == nil {
+		a.mu.Unlock()
+		return session, fmt.Errorf("not in champ select")
+	}
+	body, err := json.Marshal(a.champSelect)
+	a.mu.Unlock()
+	if err != nil {
+		return session, err
+	}
+	if err := json.Unmarshal(body, &session); err != nil {
+		return session, fmt.Errorf("failed to decode champ select session: %w", err)
+	}
+	return session, nil
+}
+
+// GetCurrentAction returns the local player's in-progress pick or ban, or nil
+// when it isn't their turn
+func (a *App) GetCurrentAction() (*Action, error) {
+	session, err := a.currentSession()
+	if err != nil {
+		return nil, err
+	}
+	action, ok := CurrentAction(session)
+	if !ok {
+		return nil, nil
+	}
+	return &action, nil
+}
+
+// PickTurn is the local player's place in the pick order, as reported by LocalPickTurn
+type PickTurn struct {
+	Turn    int  `json:"turn"`    // pick turns before theirs; 0 in the current turn, -1 when they have no pick left
+	OnClock bool `json:"onClock"` // their pick is in progress
+}
+
+// GetPickTurn reports how many pick turns remain before the local player's pick
+func (a *App) GetPickTurn() (PickTurn, error) {
+	session, err := a.currentSession()
+	if err != nil {
+		return PickTurn{}, err
+	}
+	turn, onClock := LocalPickTurn(session)
+	return PickTurn{Turn: turn, OnClock: onClock}, nil
+}
+
 // extractChampSelect normalizes a champ-select websocket payload and returns the session body plus an "ended" flag.
 // Expected shapes:
 // - []any{..., "...event name...", map{"eventType": "...", "data": {...}}}
 // - map{"eventType": "...", "data": {...}} (fallback)
+// - map{...session fields...} (bare session, returned as-is)
+// - ChampSelectSession (decoded by the live connector)
+// Events with a null or non-object "data" are only surfaced when they are Deletes.
 func (a *App) extractChampSelect(raw interface{}) (map[string]interface{}, bool) {
 	var event map[string]interface{}
 	ended := false
@@ -577,6 +2051,17 @@ func (a *App) extractChampSelect(raw interface{}) (map[string]interface{}, bool)
 		}
 	case map[string]interface{}:
 		event = v
+	case ChampSelectSession:
+		// The live connector has already decoded the session; re-encode it as a plain map
+		body, err := json.Marshal(v)
+		if err != nil {
+			return nil, false
+		}
+		var session map[string]interface{}
+		if err := json.Unmarshal(body, &session); err != nil {
+			return nil, false
+		}
+		event = map[string]interface{}{"eventType": "Update", "data": session}
 	default:
 		return nil, false
 	}
@@ -590,13 +2075,51 @@ func (a *App) extractChampSelect(raw interface{}) (map[string]interface{}, bool)
 	}
 
 	// Prefer the "data" field if present; fallback to whole event if not.
-	if data, ok := event["data"].(map[string]interface{}); ok {
-		return data, ended
+	data, hasData := event["data"]
+	if m, ok := data.(map[string]interface{}); ok {
+		if !ended && isEmptySession(m) {
+			return nil, false
+		}
+		return m, ended
+	}
+
+	// An Update/Create whose data is null (or not an object) carries no session;
+	// returning the envelope would be mistaken for one.
+	if hasData && !ended {
+		return nil, false
+	}
+
+	if !hasData && !ended && isEmptySession(event) {
+		return nil, false
 	}
 
 	return event, ended
 }
 
+// isEmptySession reports whether a session body has no teams and no actions.
+// The LCU sends these mid-transition and emitting them would blank the overlay.
+func isEmptySession(session map[string]interface{}) bool {
+	for _, key := range []string{"myTeam", "theirTeam", "actions"} {
+		if arr, ok := session[key].([]interface{}); ok && len(arr) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// numberValue reads a JSON number that may have been decoded as float64 or built as an int
+func numberValue(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
 // mockLCUResponse returns lightweight placeholder responses for mock mode to keep
 // frontend flows alive without hitting the real LCU HTTP endpoints.
 func (a *App) mockLCUResponse(endpoint string) (map[string]interface{}, error) {
@@ -629,10 +2152,72 @@ func (a *App) mockLCUResponse(endpoint string) (map[string]interface{}, error) {
 			"puuid":         "mock-puuid",
 			"mock":          true,
 		}, nil
+	case strings.HasPrefix(endpoint, "/lol-perks/v1/currentpage"):
+		return map[string]interface{}{
+			"id":              1,
+			"name":            "Mock Runes",
+			"primaryStyleId":  8000,
+			"subStyleId":      8400,
+			"selectedPerkIds": []int{8010, 9111, 9104, 8299, 8444, 8242, 5005, 5008, 5001},
+			"isEditable":      true,
+			"current":         true,
+			"mock":            true,
+		}, nil
+	case strings.HasPrefix(endpoint, "/lol-perks/v1/pages"):
+		// Rune writes are accepted and ignored in mock mode
+		return map[string]interface{}{
+			"id":      1,
+			"success": true,
+			"mock":    true,
+		}, nil
+	case strings.HasPrefix(endpoint, "/lol-champ-select/v1/session"):
+		return map[string]interface{}{
+			"localPlayerCellId": 0,
+			"myTeam": []interface{}{
+				map[string]interface{}{
+					"cellId":   0,
+					"spell1Id": 4,
+					"spell2Id": 14,
+				},
+			},
+			// Both turns are open so LockIn and BanChampion work in mock mode
+			"actions": []interface{}{
+				[]interface{}{map[string]interface{}{"id": 1, "actorCellId": 0, "type": "ban", "isInProgress": true}},
+				[]interface{}{map[string]interface{}{"id": 2, "actorCellId": 0, "type": "pick", "isInProgress": true}},
+			},
+			"mock": true,
+		}, nil
+	case strings.HasPrefix(endpoint, "/lol-game-queues/v1/queues/"):
+		// Every mock queue plays like ranked solo on Summoner's Rift
+		return map[string]interface{}{
+			"id":       420,
+			"name":     "Ranked Solo/Duo",
+			"gameMode": "CLASSIC",
+			"mapId":    11,
+			"mock":     true,
+		}, nil
+	case strings.HasPrefix(endpoint, "/lol-maps/v1/map/"):
+		return map[string]interface{}{
+			"id":   11,
+			"name": "Summoner's Rift",
+			"assets": map[string]interface{}{
+				"champ-select-background-sound": "lol-game-data/assets/content/src/LeagueClient/GameModeAssets/Classic_SRU/sound/sfx-cs-background-sr.ogg",
+				"game-select-icon-active":       "lol-game-data/assets/content/src/LeagueClient/GameModeAssets/Classic_SRU/img/game-select-icon-active.png",
+				"gameflow-background":           "lol-game-data/assets/content/src/LeagueClient/GameModeAssets/Classic_SRU/img/gameflow-background.jpg",
+			},
+			"mock": true,
+		}, nil
+	case strings.HasPrefix(endpoint, "/lol-lobby/v2/lobby/matchmaking/quit-dodge"):
+		// Dodging is a no-op in mock mode
+		return map[string]interface{}{}, nil
 	case strings.HasPrefix(endpoint, "/lol-match-history/v1/products/lol/current-summoner/matches"):
+		now := time.Now()
 		return map[string]interface{}{
 			"games": map[string]interface{}{
-				"games": []map[string]interface{}{},
+				"games": []map[string]interface{}{
+					mockMatch(9000000002, 420, "CLASSIC", 157, true, 9, 3, 7, now.Add(-time.Hour), 1834),
+					mockMatch(9000000001, 450, "ARAM", 22, false, 4, 11, 19, now.Add(-3*time.Hour), 1206),
+				},
 			},
 			"mock": true,
 		}, nil
@@ -643,3 +2228,25 @@ func (a *App) mockLCUResponse(endpoint string) (map[string]interface{}, error) {
 		}, nil
 	}
 }
+
+// mockMatch builds a match-history game in the shape the LCU returns
+func mockMatch(gameID int64, queueID int, gameMode string, championID int, win bool, kills, deaths, assists int, playedAt time.Time, duration int) map[string]interface{} {
+	return map[string]interface{}{
+		"gameId":       gameID,
+		"queueId":      queueID,
+		"gameMode":     gameMode,
+		"gameCreation": playedAt.UnixMilli(),
+		"gameDuration": duration,
+		"participants": []map[string]interface{}{
+			{
+				"championId": championID,
+				"stats": map[string]interface{}{
+					"win":     win,
+					"kills":   kills,
+					"deaths":  deaths,
+					"assists": assists,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestExtractChampSelect(t *testing.T) {
+	session := map[string]interface{}{
+		"myTeam":    []interface{}{map[string]interface{}{"cellId": float64(0), "championId": float64(157)}},
+		"theirTeam": []interface{}{},
+		"actions":   []interface{}{},
+	}
+	emptySession := map[string]interface{}{
+		"myTeam":    []interface{}{},
+		"theirTeam": []interface{}{},
+		"actions":   []interface{}{},
+	}
+
+	tests := []struct {
+		name      string
+		raw       interface{}
+		want      map[string]interface{}
+		wantEnded bool
+	}{
+		{
+			name: "full array update",
+			raw: []interface{}{float64(8), "OnJsonApiEvent_lol-champ-select_v1_session", map[string]interface{}{
+				"eventType": "Update",
+				"uri":       "/lol-champ-select/v1/session",
+				"data":      session,
+			}},
+			want: session,
+		},
+		{
+			name: "full array delete",
+			raw: []interface{}{float64(8), "OnJsonApiEvent_lol-champ-select_v1_session", map[string]interface{}{
+				"eventType": "Delete",
+				"data":      session,
+			}},
+			want:      session,
+			wantEnded: true,
+		},
+		{
+			name: "array too short",
+			raw:  []interface{}{float64(8), "OnJsonApiEvent_lol-champ-select_v1_session"},
+		},
+		{
+			name: "event map",
+			raw:  map[string]interface{}{"eventType": "Update", "data": session},
+			want: session,
+		},
+		{
+			name: "bare session map",
+			raw:  session,
+			want: session,
+		},
+		{
+			name: "bare empty session map",
+			raw:  emptySession,
+		},
+		{
+			name: "update with empty session",
+			raw:  map[string]interface{}{"eventType": "Update", "data": emptySession},
+		},
+		{
+			name: "update missing data",
+			raw:  map[string]interface{}{"eventType": "Update", "uri": "/lol-champ-select/v1/session"},
+		},
+		{
+			name: "update with null data",
+			raw: []interface{}{float64(8), "OnJsonApiEvent_lol-champ-select_v1_session", map[string]interface{}{
+				"eventType": "Update",
+				"data":      nil,
+			}},
+		},
+		{
+			name: "update with non-object data",
+			raw:  map[string]interface{}{"eventType": "Update", "data": "gone"},
+		},
+		{
+			name:      "delete marker without data",
+			raw:       map[string]interface{}{"eventType": "Delete", "uri": "/lol-champ-select/v1/session"},
+			want:      map[string]interface{}{"eventType": "Delete", "uri": "/lol-champ-select/v1/session"},
+			wantEnded: true,
+		},
+		{
+			name: "delete marker with null data",
+			raw: []interface{}{float64(8), "OnJsonApiEvent_lol-champ-select_v1_session", map[string]interface{}{
+				"eventType": "Delete",
+				"data":      nil,
+			}},
+			want:      map[string]interface{}{"eventType": "Delete", "data": nil},
+			wantEnded: true,
+		},
+		{
+			name:      "delete event type is case-insensitive",
+			raw:       map[string]interface{}{"eventType": "delete"},
+			want:      map[string]interface{}{"eventType": "delete"},
+			wantEnded: true,
+		},
+		{
+			name: "unsupported payload",
+			raw:  "OnJsonApiEvent",
+		},
+	}
+
+	a := &App{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ended := a.extractChampSelect(tt.raw)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("session = %#v, want %#v", got, tt.want)
+			}
+			if ended != tt.wantEnded {
+				t.Errorf("ended = %v, want %v", ended, tt.wantEnded)
+			}
+		})
+	}
+}
+
+func TestExtractChampSelectTypedSession(t *testing.T) {
+	a := &App{}
+
+	var session ChampSelectSession
+	body := `{"localPlayerCellId": 2, "gameId": 7, "myTeam": [{"cellId": 2, "championId": 157}]}`
+	if err := json.Unmarshal([]byte(body), &session); err != nil {
+		t.Fatal(err)
+	}
+
+	got, ended := a.extractChampSelect(session)
+	if ended {
+		t.Error("ended = true, want false")
+	}
+	if got == nil {
+		t.Fatal("session = nil, want the re-encoded session")
+	}
+	if got["localPlayerCellId"] != float64(2) || got["gameId"] != float64(7) {
+		t.Errorf("localPlayerCellId, gameId = %v, %v; want 2, 7", got["localPlayerCellId"], got["gameId"])
+	}
+	myTeam, ok := got["myTeam"].([]interface{})
+	if !ok || len(myTeam) != 1 {
+		t.Fatalf("myTeam = %#v, want one member", got["myTeam"])
+	}
+	if member, _ := myTeam[0].(map[string]interface{}); member["championId"] != float64(157) {
+		t.Errorf("myTeam[0] = %#v, want championId 157", myTeam[0])
+	}
+
+	// A typed session with no teams or actions is a mid-transition blank
+	if got, ended := a.extractChampSelect(ChampSelectSession{}); got != nil || ended {
+		t.Errorf("empty typed session = %#v, %v; want nil, false", got, ended)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !windows
+
+package main
+
+// installConsoleCloseHandler is a no-op outside Windows; SIGTERM and SIGHUP
+// are handled through os/signal instead.
+func installConsoleCloseHandler(flush func()) {}
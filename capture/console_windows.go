@@ -0,0 +1,36 @@
+//go:build windows
+
+package main
+
+import (
+	"syscall"
+)
+
+// Console control events sent when the terminal window is closed or the user
+// logs off. Windows kills the process as soon as the handler returns, so the
+// capture has to be flushed before returning.
+const (
+	ctrlCloseEvent    = 2
+	ctrlLogoffEvent   = 5
+	ctrlShutdownEvent = 6
+)
+
+var (
+	kernel32                  = syscall.NewLazyDLL("kernel32.dll")
+	procSetConsoleCtrlHandler = kernel32.NewProc("SetConsoleCtrlHandler")
+)
+
+// installConsoleCloseHandler runs flush synchronously when the console window
+// is closed, which SIGTERM handling does not reliably cover on Windows.
+func installConsoleCloseHandler(flush func()) {
+	handler := syscall.NewCallback(func(ctrlType uint32) uintptr {
+		switch ctrlType {
+		case ctrlCloseEvent, ctrlLogoffEvent, ctrlShutdownEvent:
+			flush()
+			return 1
+		}
+		// Let Ctrl+C and Ctrl+Break fall through to os/signal
+		return 0
+	})
+	procSetConsoleCtrlHandler.Call(handler, 1)
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"net/http"
 	"os"
@@ -14,6 +15,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -22,6 +25,9 @@ import (
 	"github.com/coder/websocket"
 	"github.com/fsnotify/fsnotify"
 	"github.com/shirou/gopsutil/v3/process"
+
+	"rez/internal/lcupath"
+	"rez/internal/mockreplay"
 )
 
 // Types from connector.go
@@ -42,49 +48,137 @@ type LCUConnector struct {
 	OnConnect          chan ConnectionInfo
 	OnDisconnect       chan struct{}
 	OnChampSelect      chan interface{} // Raw JSON data
-	OnChampSelectEnded chan struct{}
+	OnChampSelectEnded chan interface{} // Raw Delete payload
+	OnChat             chan interface{} // Raw champ-select chat payloads
+	topics             []string         // Full event names to subscribe to; defaults to champ select
 	wsConn             *websocket.Conn
 	wsContext          context.Context
 	wsCancel           context.CancelFunc
 }
 
+const champSelectTopic = "OnJsonApiEvent_lol-champ-select_v1_session"
+
+// chatTopic carries every chat conversation; only the champ-select one is kept
+const chatTopic = "OnJsonApiEvent_lol-chat_v1_conversations"
+
+// topicCatalog maps friendly names accepted by -topics to LCU event names
+var topicCatalog = map[string]string{
+	"champ-select": champSelectTopic,
+	"chat":         chatTopic,
+	"gameflow":     "OnJsonApiEvent_lol-gameflow_v1_gameflow-phase",
+	"lobby":        "OnJsonApiEvent_lol-lobby_v2_lobby",
+	"ready-check":  "OnJsonApiEvent_lol-matchmaking_v1_ready-check",
+}
+
+// resolveTopics turns a comma-separated list of catalog names (or full
+// OnJsonApiEvent_ names) into event names. Champ select is always included
+// since it drives the start and end of a capture.
+func resolveTopics(spec string) ([]string, error) {
+	topics := []string{champSelectTopic}
+	seen := map[string]bool{champSelectTopic: true}
+
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		topic, ok := topicCatalog[name]
+		if !ok {
+			if !strings.HasPrefix(name, "OnJsonApiEvent") {
+				return nil, fmt.Errorf("unknown topic %q (known: %s)", name, strings.Join(topicNames(), ", "))
+			}
+			topic = name
+		}
+
+		if !seen[topic] {
+			seen[topic] = true
+			topics = append(topics, topic)
+		}
+	}
+
+	return topics, nil
+}
+
+// topicNames lists the catalog's friendly names in sorted order
+func topicNames() []string {
+	names := make([]string, 0, len(topicCatalog))
+	for name := range topicCatalog {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // CapturedEvent represents a single captured event with timestamp and raw data
 type CapturedEvent struct {
-	Timestamp string      `json:"timestamp"`
-	RawData   interface{} `json:"rawData"` // Raw JSON data from WebSocket
+	Timestamp       string      `json:"timestamp"`
+	OffsetMs        int64       `json:"offsetMs"` // Monotonic offset from capture start; immune to wall-clock changes
+	GameID          int64       `json:"gameId,omitempty"`
+	ClientLatencyMs *int64      `json:"clientLatencyMs,omitempty"` // Receive time minus the client's timer.internalNowInEpochMs
+	Kind            string      `json:"kind,omitempty"`            // "chat" for champ-select chat; empty for session events
+	GapCompressed   bool        `json:"gapCompressed,omitempty"`   // Follows a pause longer than -gap-threshold; replays may shorten it
+	RawData         interface{} `json:"rawData"`                   // Raw JSON data from WebSocket
 }
 
 // CaptureSession represents a complete capture session
 type CaptureSession struct {
-	StartTime  string          `json:"startTime"`
-	EndTime    string          `json:"endTime,omitempty"`
-	EventCount int             `json:"eventCount"`
-	Events     []CapturedEvent `json:"events"`
+	StartTime  string            `json:"startTime"`
+	EndTime    string            `json:"endTime,omitempty"`
+	EventCount int               `json:"eventCount"`
+	Metadata   map[string]string `json:"metadata,omitempty"` // From -tag, e.g. patch=14.3
+	Events     []CapturedEvent   `json:"events"`
 }
 
 type ChampSelectCapturer struct {
 	connector   *LCUConnector
 	session     *CaptureSession
+	startedAt   time.Time // Carries a monotonic reading used for event offsets
 	outputFile  string
 	isCapturing bool
 	mu          sync.Mutex
 	done        chan struct{}
 	shouldExit  bool
 	doneOnce    sync.Once
+	stopOnce    sync.Once
+	anonymize   bool // Replace player identifiers before writing to disk
+	localOnly   bool // Reduce each event to the local player's perspective
+	maxEvents   int  // Finish the capture after this many events; 0 means no limit
+
+	handlers sync.WaitGroup // The connection and event goroutines started by Start
+
+	connectTimeout time.Duration // Give up if the LCU isn't reached within this long; 0 waits forever
+
+	gapThreshold time.Duration // Pauses longer than this mark the next event gapCompressed; 0 disables
+	lastEventAt  time.Time     // When the previous event was captured
+
+	outputTemplate string // Set when outputFile has placeholders; resolved on the first event
+	region         string // From /riotclient/region-locale, for the {region} placeholder
 }
 
+// NewCapturer creates a capturer writing to outputFile. The name may contain
+// {date}, {queue}, {region} and {gameId} placeholders, which are filled from
+// the first champ-select event.
 func NewCapturer(outputFile string) *ChampSelectCapturer {
 	if outputFile == "" {
 		timestamp := time.Now().Format("20060102_150405")
 		outputFile = fmt.Sprintf("champ-select-capture_%s.json", timestamp)
 	}
 
+	var outputTemplate string
+	if strings.Contains(outputFile, "{") {
+		outputTemplate, outputFile = outputFile, ""
+	}
+
+	startedAt := time.Now()
 	return &ChampSelectCapturer{
-		connector:  NewLCUConnector(""),
-		outputFile: outputFile,
-		done:       make(chan struct{}),
+		connector:      NewLCUConnector(""),
+		outputFile:     outputFile,
+		outputTemplate: outputTemplate,
+		startedAt:      startedAt,
+		done:           make(chan struct{}),
 		session: &CaptureSession{
-			StartTime:  time.Now().Format(time.RFC3339),
+			StartTime:  startedAt.Format(time.RFC3339),
 			EventCount: 0,
 			Events:     make([]CapturedEvent, 0),
 		},
@@ -93,7 +187,12 @@ func NewCapturer(outputFile string) *ChampSelectCapturer {
 
 func (c *ChampSelectCapturer) Start() error {
 	fmt.Println("Starting champion select capture...")
-	fmt.Printf("Output file: %s\n", c.outputFile)
+	if c.outputTemplate != "" {
+		fmt.Printf("Output file: %s (resolved on first event)\n", c.outputTemplate)
+	} else {
+		fmt.Printf("Output file: %s\n", c.outputFile)
+	}
+	fmt.Printf("Topics: %s\n", strings.Join(c.connector.topics, ", "))
 	fmt.Println("Waiting for LCU connection and champion select...")
 	fmt.Println("Press Ctrl+C to stop capturing")
 
@@ -102,16 +201,29 @@ func (c *ChampSelectCapturer) Start() error {
 
 	// Set up signal handling for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	// SIGHUP covers the terminal closing on Unix; Windows console close is handled separately
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	installConsoleCloseHandler(c.Stop)
 
 	// Handle LCU connection events
+	connected := make(chan struct{})
+	c.handlers.Add(2)
 	go func() {
+		defer c.handlers.Done()
+		notified := false
 		for {
 			select {
 			case <-c.done:
 				return
 			case info := <-c.connector.OnConnect:
 				fmt.Printf("✓ Connected to LCU at %s:%s\n", info.Address, info.Port)
+				if !notified {
+					close(connected)
+					notified = true
+				}
+				if c.outputTemplate != "" {
+					go c.fetchRegion(info)
+				}
 			case <-c.connector.OnDisconnect:
 				fmt.Println("✗ Disconnected from LCU")
 				if c.isCapturing {
@@ -123,15 +235,18 @@ func (c *ChampSelectCapturer) Start() error {
 
 	// Handle champion select events
 	go func() {
+		defer c.handlers.Done()
 		for {
 			select {
 			case <-c.done:
 				return
 			case rawData := <-c.connector.OnChampSelect:
 				c.handleChampSelectEvent(rawData)
-			case <-c.connector.OnChampSelectEnded:
+			case rawData := <-c.connector.OnChat:
+				c.handleChatEvent(rawData)
+			case rawData := <-c.connector.OnChampSelectEnded:
 				if c.isCapturing {
-					c.handleChampSelectEnded()
+					c.handleChampSelectEnded(rawData)
 					// Auto-stop after champ select ends
 					c.mu.Lock()
 					shouldExit := c.shouldExit
@@ -145,34 +260,70 @@ func (c *ChampSelectCapturer) Start() error {
 		}
 	}()
 
-	// Wait for interrupt signal or done channel
-	select {
-	case <-sigChan:
-		fmt.Println("\nStopping capture...")
-		c.Stop()
-	case <-c.done:
-		fmt.Println("\nChampion select ended, stopping capture...")
-		c.Stop()
+	var connectDeadline <-chan time.Time
+	if c.connectTimeout > 0 {
+		timer := time.NewTimer(c.connectTimeout)
+		defer timer.Stop()
+		connectDeadline = timer.C
 	}
 
-	return nil
+	// Wait for interrupt signal or done channel
+	for {
+		select {
+		case <-sigChan:
+			fmt.Println("\nStopping capture...")
+			c.Stop()
+			return nil
+		case <-c.done:
+			fmt.Println("\nCapture finished, stopping...")
+			c.Stop()
+			return nil
+		case <-connected:
+			connected, connectDeadline = nil, nil
+		case <-connectDeadline:
+			// Nothing was captured, so there is no file to finalize
+			c.signalDone()
+			c.handlers.Wait()
+			c.connector.Stop()
+			return fmt.Errorf("no LCU connection within %s; is the League client running?", c.connectTimeout)
+		}
+	}
 }
 
 func (c *ChampSelectCapturer) handleChampSelectEvent(rawData interface{}) {
 	c.mu.Lock()
 
+	if c.maxEvents > 0 && c.session.EventCount >= c.maxEvents {
+		// Limit already reached; we're on our way out
+		c.mu.Unlock()
+		return
+	}
+
 	if !c.isCapturing {
 		// First event - start capturing and create file
 		c.isCapturing = true
+		c.resolveOutputFile(rawData)
 		fmt.Printf("\n=== Champion Select Started ===\n")
 		fmt.Println("Capturing raw events...")
 	}
 
+	if c.localOnly {
+		rawData = localPerspective(rawData)
+	}
+
 	// Capture raw event data
+	now := time.Now()
 	capturedEvent := CapturedEvent{
-		Timestamp: time.Now().Format(time.RFC3339Nano),
+		Timestamp: now.Format(time.RFC3339Nano),
+		OffsetMs:  now.Sub(c.startedAt).Milliseconds(),
+		GameID:    gameIDFromPayload(rawData),
 		RawData:   rawData,
 	}
+	if sentAt, ok := clientNowFromPayload(rawData); ok {
+		latency := now.UnixMilli() - sentAt
+		capturedEvent.ClientLatencyMs = &latency
+	}
+	c.markGap(&capturedEvent, now)
 
 	c.session.Events = append(c.session.Events, capturedEvent)
 	c.session.EventCount = len(c.session.Events)
@@ -181,14 +332,236 @@ func (c *ChampSelectCapturer) handleChampSelectEvent(rawData interface{}) {
 		capturedEvent.Timestamp,
 		c.session.EventCount)
 
+	limitReached := c.maxEvents > 0 && c.session.EventCount >= c.maxEvents
+	if limitReached {
+		c.session.EndTime = now.Format(time.RFC3339)
+		fmt.Printf("\nReached -max-events %d\n", c.maxEvents)
+	}
+
 	c.mu.Unlock()
 
 	if err := c.persistSession(c.snapshotSession()); err != nil {
 		fmt.Printf("Warning: failed to persist capture: %v\n", err)
 	}
+
+	if limitReached {
+		// Start's select picks this up and runs Stop to finalize the file
+		c.signalDone()
+	}
 }
 
-func (c *ChampSelectCapturer) handleChampSelectEnded() {
+// handleChatEvent records a champ-select chat message alongside the session
+// events. Messages outside an active capture are dropped.
+func (c *ChampSelectCapturer) handleChatEvent(rawData interface{}) {
+	c.mu.Lock()
+	if !c.isCapturing || (c.maxEvents > 0 && c.session.EventCount >= c.maxEvents) {
+		c.mu.Unlock()
+		return
+	}
+
+	now := time.Now()
+	chatEvent := CapturedEvent{
+		Timestamp: now.Format(time.RFC3339Nano),
+		OffsetMs:  now.Sub(c.startedAt).Milliseconds(),
+		Kind:      "chat",
+		RawData:   rawData,
+	}
+	c.markGap(&chatEvent, now)
+	c.session.Events = append(c.session.Events, chatEvent)
+	c.session.EventCount = len(c.session.Events)
+	fmt.Printf("[%s] Chat message #%d captured\n", now.Format(time.RFC3339Nano), c.session.EventCount)
+	c.mu.Unlock()
+
+	if err := c.persistSession(c.snapshotSession()); err != nil {
+		fmt.Printf("Warning: failed to persist capture: %v\n", err)
+	}
+}
+
+// markGap flags an event that follows a long pause, such as a remake or a
+// reconnect, so replays know the gap can be shortened. Callers must hold c.mu.
+func (c *ChampSelectCapturer) markGap(ev *CapturedEvent, now time.Time) {
+	if c.gapThreshold > 0 && !c.lastEventAt.IsZero() {
+		if gap := now.Sub(c.lastEventAt); gap > c.gapThreshold {
+			ev.GapCompressed = true
+			fmt.Printf("Pause of %s before this event\n", gap.Round(time.Second))
+		}
+	}
+	c.lastEventAt = now
+}
+
+// isChampSelectChat reports whether a chat payload belongs to the champ-select
+// conversation, whose id ends in @champ-select.<region>.pvp.net
+func isChampSelectChat(rawData []any) bool {
+	eventData, ok := rawData[2].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	uri, _ := eventData["uri"].(string)
+	return strings.Contains(uri, "champ-select")
+}
+
+// summonerIdentifiers are the per-player fields dropped by localPerspective
+var summonerIdentifiers = []string{"gameName", "tagLine", "puuid", "summonerId", "obfuscatedPuuid", "obfuscatedSummonerId"}
+
+// localPerspective strips a champ-select payload down to what a pick assistant
+// needs: enemy entries keep only their cell and champion, and summoner
+// identifiers are removed from everyone.
+func localPerspective(rawData interface{}) interface{} {
+	payload, ok := rawData.([]any)
+	if !ok || len(payload) < 3 {
+		return rawData
+	}
+	eventData, ok := payload[2].(map[string]interface{})
+	if !ok {
+		return rawData
+	}
+	data, ok := eventData["data"].(map[string]interface{})
+	if !ok {
+		return rawData
+	}
+
+	if theirTeam, ok := data["theirTeam"].([]interface{}); ok {
+		reduced := make([]interface{}, 0, len(theirTeam))
+		for _, entry := range theirTeam {
+			member, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			reduced = append(reduced, map[string]interface{}{
+				"cellId":     member["cellId"],
+				"championId": member["championId"],
+			})
+		}
+		data["theirTeam"] = reduced
+	}
+
+	if myTeam, ok := data["myTeam"].([]interface{}); ok {
+		for _, entry := range myTeam {
+			if member, ok := entry.(map[string]interface{}); ok {
+				for _, key := range summonerIdentifiers {
+					delete(member, key)
+				}
+			}
+		}
+	}
+
+	return rawData
+}
+
+// clientNowFromPayload extracts data.timer.internalNowInEpochMs, the client's clock at send time
+func clientNowFromPayload(rawData interface{}) (int64, bool) {
+	payload, ok := rawData.([]any)
+	if !ok || len(payload) < 3 {
+		return 0, false
+	}
+	eventData, ok := payload[2].(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	data, ok := eventData["data"].(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	timer, ok := data["timer"].(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	sentAt, ok := timer["internalNowInEpochMs"].(float64)
+	if !ok || sentAt <= 0 {
+		return 0, false
+	}
+	return int64(sentAt), true
+}
+
+// gameIDFromPayload extracts data.gameId from a raw [type, name, event] payload.
+func gameIDFromPayload(rawData interface{}) int64 {
+	return sessionNumber(rawData, "gameId")
+}
+
+// sessionNumber reads a numeric field from the session in a [type, name, event] payload
+func sessionNumber(rawData interface{}, key string) int64 {
+	payload, ok := rawData.([]any)
+	if !ok || len(payload) < 3 {
+		return 0
+	}
+	eventData, ok := payload[2].(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	data, ok := eventData["data"].(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	if n, ok := data[key].(float64); ok {
+		return int64(n)
+	}
+	return 0
+}
+
+// resolveOutputFile fills the output template from the first event. Callers must hold c.mu.
+func (c *ChampSelectCapturer) resolveOutputFile(rawData interface{}) {
+	if c.outputTemplate == "" || c.outputFile != "" {
+		return
+	}
+
+	placeholder := func(n int64) string {
+		if n == 0 {
+			return "unknown"
+		}
+		return strconv.FormatInt(n, 10)
+	}
+	region := c.region
+	if region == "" {
+		region = "unknown"
+	}
+
+	c.outputFile = strings.NewReplacer(
+		"{date}", c.startedAt.Format("20060102_150405"),
+		"{queue}", placeholder(sessionNumber(rawData, "queueId")),
+		"{region}", strings.ToLower(region),
+		"{gameId}", placeholder(sessionNumber(rawData, "gameId")),
+	).Replace(c.outputTemplate)
+	fmt.Printf("Output file: %s\n", c.outputFile)
+}
+
+// fetchRegion looks up the client's region for the {region} placeholder.
+func (c *ChampSelectCapturer) fetchRegion(info ConnectionInfo) {
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+
+	url := fmt.Sprintf("https://%s:%s/riotclient/region-locale", info.Address, info.Port)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return
+	}
+	req.SetBasicAuth(info.Username, info.Password)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Printf("Warning: could not fetch region: %v\n", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	var regionLocale struct {
+		Region string `json:"region"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&regionLocale); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.region = regionLocale.Region
+	c.mu.Unlock()
+}
+
+// handleChampSelectEnded records the LCU's Delete frame as the final event.
+// A synthetic marker is only written when no frame is available.
+func (c *ChampSelectCapturer) handleChampSelectEnded(rawData interface{}) {
 	c.mu.Lock()
 
 	if !c.isCapturing {
@@ -196,12 +569,18 @@ func (c *ChampSelectCapturer) handleChampSelectEnded() {
 		return
 	}
 
-	// Add Delete event marker
-	deleteEvent := CapturedEvent{
-		Timestamp: time.Now().Format(time.RFC3339Nano),
-		RawData: map[string]interface{}{
+	if rawData == nil {
+		rawData = map[string]interface{}{
 			"eventType": "Delete",
-		},
+		}
+	}
+
+	now := time.Now()
+	deleteEvent := CapturedEvent{
+		Timestamp: now.Format(time.RFC3339Nano),
+		OffsetMs:  now.Sub(c.startedAt).Milliseconds(),
+		GameID:    gameIDFromPayload(rawData),
+		RawData:   rawData,
 	}
 
 	c.session.Events = append(c.session.Events, deleteEvent)
@@ -230,6 +609,8 @@ func (c *ChampSelectCapturer) endSession() {
 
 func (c *ChampSelectCapturer) finalizeFile() {
 	c.mu.Lock()
+	// Stopped before any event arrived; fill the template with what we know
+	c.resolveOutputFile(nil)
 	endTime := c.session.EndTime
 	eventCount := c.session.EventCount
 	c.mu.Unlock()
@@ -263,22 +644,28 @@ func (c *ChampSelectCapturer) signalDone() {
 	})
 }
 
+// Stop finalizes the capture and shuts down the connector. It is safe to call
+// more than once, e.g. from both the console handler and the signal path.
 func (c *ChampSelectCapturer) Stop() {
-	// Mark session as ended if needed
-	c.mu.Lock()
-	if c.isCapturing && c.session.EndTime == "" {
-		c.session.EndTime = time.Now().Format(time.RFC3339)
-	}
-	c.mu.Unlock()
-
-	// Finalize file (this needs to happen without lock)
-	c.finalizeFile()
+	c.stopOnce.Do(func() {
+		// Stop the handlers and let an event already being written land
+		// before the file is finalized and the summary printed
+		c.signalDone()
+		c.handlers.Wait()
+
+		// Mark session as ended if needed
+		c.mu.Lock()
+		if c.isCapturing && c.session.EndTime == "" {
+			c.session.EndTime = time.Now().Format(time.RFC3339)
+		}
+		c.mu.Unlock()
 
-	// Signal all goroutines to stop
-	c.signalDone()
+		// Finalize file (this needs to happen without lock)
+		c.finalizeFile()
 
-	// Stop connector
-	c.connector.Stop()
+		// Stop connector
+		c.connector.Stop()
+	})
 }
 
 func (c *ChampSelectCapturer) snapshotSession() CaptureSession {
@@ -292,37 +679,37 @@ func (c *ChampSelectCapturer) snapshotSession() CaptureSession {
 		StartTime:  c.session.StartTime,
 		EndTime:    c.session.EndTime,
 		EventCount: c.session.EventCount,
+		Metadata:   c.session.Metadata,
 		Events:     eventsCopy,
 	}
 }
 
 func (c *ChampSelectCapturer) persistSession(snapshot CaptureSession) error {
-	return writeJSONAtomic(c.outputFile, snapshot)
-}
-
-func writeJSONAtomic(path string, v interface{}) error {
-	dir := filepath.Dir(path)
-	if dir != "" && dir != "." {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("error creating output directory: %v", err)
-		}
+	if c.anonymize {
+		return c.persistAnonymized(snapshot)
 	}
+	return mockreplay.WriteCapture(c.outputFile, snapshot)
+}
 
-	data, err := json.MarshalIndent(v, "", "  ")
+// persistAnonymized round-trips the snapshot through mockreplay so identifiers
+// never reach disk.
+func (c *ChampSelectCapturer) persistAnonymized(snapshot CaptureSession) error {
+	data, err := json.Marshal(snapshot)
 	if err != nil {
 		return err
 	}
 
-	tmp := path + ".tmp"
-	if err := os.WriteFile(tmp, data, 0644); err != nil {
+	var session mockreplay.CaptureSession
+	if err := json.Unmarshal(data, &session); err != nil {
 		return err
 	}
 
-	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
-		return err
+	anonymized, err := mockreplay.Anonymize(&session)
+	if err != nil {
+		return fmt.Errorf("anonymize capture: %v", err)
 	}
 
-	return os.Rename(tmp, path)
+	return mockreplay.WriteCapture(c.outputFile, anonymized)
 }
 
 // LCU Connector implementation (copied from connector.go)
@@ -331,7 +718,9 @@ func NewLCUConnector(executablePath string) *LCUConnector {
 		OnConnect:          make(chan ConnectionInfo),
 		OnDisconnect:       make(chan struct{}),
 		OnChampSelect:      make(chan interface{}), // Raw JSON data
-		OnChampSelectEnded: make(chan struct{}),
+		OnChampSelectEnded: make(chan interface{}), // Raw Delete payload
+		OnChat:             make(chan interface{}), // Raw champ-select chat payloads
+		topics:             []string{champSelectTopic},
 		stopCh:             make(chan struct{}),
 	}
 	if executablePath != "" {
@@ -341,7 +730,7 @@ func NewLCUConnector(executablePath string) *LCUConnector {
 }
 
 func (l *LCUConnector) Start() {
-	if IsValidLCUPath(l.dirPath) {
+	if lcupath.IsValid(l.dirPath) {
 		l.initLockfileWatcher()
 		return
 	}
@@ -368,6 +757,10 @@ func (l *LCUConnector) initProcessWatcher() {
 			select {
 			case <-l.processTicker.C:
 				path, _ := GetLCUPathFromProcess()
+				if path == "" {
+					// macOS can hide other processes' arguments; try the standard install
+					path = defaultLCUPath()
+				}
 				if path != "" {
 					l.dirPath = path
 					l.clearProcessWatcher()
@@ -516,14 +909,17 @@ func (l *LCUConnector) clearWebSocket() {
 }
 
 func (l *LCUConnector) handleWebSocket() {
-	subMsg := []any{5, "OnJsonApiEvent_lol-champ-select_v1_session"}
-	msgBytes, err := json.Marshal(subMsg)
-	if err != nil {
-		return
-	}
+	subscribed := make(map[string]bool, len(l.topics))
+	for _, topic := range l.topics {
+		msgBytes, err := json.Marshal([]any{5, topic})
+		if err != nil {
+			return
+		}
 
-	if err := l.wsConn.Write(l.wsContext, websocket.MessageText, msgBytes); err != nil {
-		return
+		if err := l.wsConn.Write(l.wsContext, websocket.MessageText, msgBytes); err != nil {
+			return
+		}
+		subscribed[topic] = true
 	}
 
 	for {
@@ -542,7 +938,7 @@ func (l *LCUConnector) handleWebSocket() {
 			}
 
 			eventType, ok := payload[1].(string)
-			if !ok || eventType != "OnJsonApiEvent_lol-champ-select_v1_session" {
+			if !ok || !subscribed[eventType] {
 				continue
 			}
 
@@ -551,14 +947,26 @@ func (l *LCUConnector) handleWebSocket() {
 			// We capture everything as-is without any type constraints
 			rawPayload := payload
 
-			// Check if it's a Delete event to signal end (but still capture it)
-			if len(payload) >= 3 {
+			if eventType == chatTopic {
+				if isChampSelectChat(payload) {
+					select {
+					case l.OnChat <- rawPayload:
+					default:
+					}
+				}
+				continue
+			}
+
+			// A champ select Delete ends the capture; forward the real frame so
+			// replays match what the LCU sends
+			if eventType == champSelectTopic {
 				if eventData, ok := payload[2].(map[string]interface{}); ok {
 					if eventType, ok := eventData["eventType"].(string); ok && eventType == "Delete" {
 						select {
-						case l.OnChampSelectEnded <- struct{}{}:
+						case l.OnChampSelectEnded <- rawPayload:
 						default:
 						}
+						continue
 					}
 				}
 			}
@@ -598,23 +1006,22 @@ func GetLCUPathFromProcess() (string, error) {
 	return "", errors.New("LCU not found")
 }
 
-func IsValidLCUPath(dir string) bool {
-	if dir == "" {
-		return false
+// defaultLCUPath returns the standard install directory when it holds a valid
+// client with a lockfile, or "" otherwise
+func defaultLCUPath() string {
+	var dir string
+	switch runtime.GOOS {
+	case "darwin":
+		dir = "/Applications/League of Legends.app/Contents/LoL"
+	case "windows":
+		dir = `C:\Riot Games\League of Legends`
+	default:
+		return ""
 	}
-	var clientFile string
-	if runtime.GOOS == "darwin" {
-		clientFile = "LeagueClient.app"
-	} else {
-		clientFile = "LeagueClient.exe"
+	if lcupath.IsValid(dir) && fileExists(filepath.Join(dir, "lockfile")) {
+		return dir
 	}
-
-	common := fileExists(filepath.Join(dir, clientFile)) && dirExists(filepath.Join(dir, "Config"))
-	isGlobal := common && dirExists(filepath.Join(dir, "RADS"))
-	isCN := common && dirExists(filepath.Join(dir, "TQM"))
-	isGarena := common
-
-	return isGlobal || isCN || isGarena
+	return ""
 }
 
 func normalizePath(p string) string {
@@ -643,14 +1050,150 @@ func dirExists(path string) bool {
 }
 
 func main() {
-	var outputFile string
-	if len(os.Args) > 1 {
-		outputFile = os.Args[1]
+	anonymize := flag.Bool("anonymize", false, "replace player names, tags, puuids and summoner ids with fake values")
+	localOnly := flag.Bool("local-only", false, "keep only enemy champion ids and drop summoner identifiers from every event")
+	maxEvents := flag.Int("max-events", 0, "stop and save after this many events (0 = until champ select ends)")
+	var tags tagFlag
+	flag.Var(&tags, "tag", "key=value metadata to store in the capture, e.g. patch=14.3 or notes=\"flex 5 stack\" (repeatable)")
+	wait := flag.Duration("wait", 0, "exit with an error if the LCU isn't connected within this long (0 = wait forever)")
+	gapThreshold := flag.Duration("gap-threshold", 30*time.Second, "mark events after a longer pause as gapCompressed so replays can shorten it (0 = off)")
+	topicList := flag.String("topics", "champ-select", "comma-separated topics to capture: "+strings.Join(topicNames(), ", ")+", or full OnJsonApiEvent_ names")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [output-file]\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "       %s info <capture-file>\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "       %s trace <capture-file>\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "       %s schema <old-capture> <new-capture>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	switch flag.Arg(0) {
+	case "info":
+		runInfo(flag.Arg(1))
+		return
+	case "trace":
+		runTrace(flag.Arg(1))
+		return
+	case "schema":
+		runSchema(flag.Arg(1), flag.Arg(2))
+		return
+	}
+
+	topics, err := resolveTopics(*topicList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
 	}
 
-	capturer := NewCapturer(outputFile)
+	capturer := NewCapturer(flag.Arg(0))
+	capturer.anonymize = *anonymize
+	capturer.localOnly = *localOnly
+	capturer.maxEvents = *maxEvents
+	capturer.gapThreshold = *gapThreshold
+	capturer.connectTimeout = *wait
+	capturer.session.Metadata = tags.values
+	capturer.connector.topics = topics
 	if err := capturer.Start(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// tagFlag collects repeated -tag key=value flags.
+type tagFlag struct {
+	values map[string]string
+}
+
+func (t *tagFlag) String() string {
+	pairs := make([]string, 0, len(t.values))
+	for key, value := range t.values {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (t *tagFlag) Set(raw string) error {
+	key, value, ok := strings.Cut(raw, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return fmt.Errorf("want key=value, got %q", raw)
+	}
+	if t.values == nil {
+		t.values = make(map[string]string)
+	}
+	t.values[key] = value
+	return nil
+}
+
+// runInfo prints summary stats for an existing capture file.
+func runInfo(path string) {
+	if path == "" {
+		fmt.Fprintln(os.Stderr, "Usage: capture info <capture-file>")
+		os.Exit(2)
+	}
+
+	info, err := mockreplay.Info(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(info)
+}
+
+// runTrace writes a normalized, diffable trace of a capture to stdout.
+func runTrace(path string) {
+	if path == "" {
+		fmt.Fprintln(os.Stderr, "Usage: capture trace <capture-file>")
+		os.Exit(2)
+	}
+
+	session, err := mockreplay.LoadCapture(path)
+	if err == nil {
+		err = session.CheckEvents()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	steps, err := mockreplay.BuildSteps(session)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	trace, err := mockreplay.ToTrace(steps)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(append(trace, '\n'))
+}
+
+// runSchema lists champ-select fields added, removed or retyped between two
+// captures, e.g. from before and after a client patch.
+func runSchema(oldPath, newPath string) {
+	if oldPath == "" || newPath == "" {
+		fmt.Fprintln(os.Stderr, "Usage: capture schema <old-capture> <new-capture>")
+		os.Exit(2)
+	}
+
+	oldSession, err := mockreplay.LoadCapture(oldPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	newSession, err := mockreplay.LoadCapture(newPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	diffs := mockreplay.CompareSchemas(oldSession, newSession)
+	if len(diffs) == 0 {
+		fmt.Println("No schema changes")
+		return
+	}
+	for _, diff := range diffs {
+		fmt.Println(diff)
+	}
+}
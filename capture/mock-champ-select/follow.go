@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"rez/internal/mockreplay"
+)
+
+// waitForCapture blocks until path is a capture with at least one event, so
+// -follow can be started before the capturer has seen champ select.
+func waitForCapture(path string) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		fmt.Fprintln(os.Stderr, "-follow needs a capture file, not a directory")
+		os.Exit(1)
+	}
+
+	announced := false
+	for {
+		if session, err := mockreplay.LoadCapture(path); err == nil && session.CheckEvents() == nil {
+			return
+		}
+		if !announced {
+			fmt.Printf("Waiting for %s to record its first event...\n", path)
+			announced = true
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// lineEditor reads REPL lines. On a terminal it supports history (up/down) and
+// tab completion of command names; piped input is read line by line as before.
+type lineEditor struct {
+	in       *bufio.Reader
+	terminal bool
+	history  []string
+	words    []string
+
+	mu      sync.Mutex
+	restore func() // leaves raw mode while a line is being edited, guarded by mu
+}
+
+func newLineEditor(words []string) *lineEditor {
+	return &lineEditor{
+		in:       bufio.NewReader(os.Stdin),
+		terminal: true, // cleared on the first read if stdin turns out not to be a terminal
+		words:    words,
+	}
+}
+
+// readLine prints prompt and returns the next line without its newline. Ctrl-C
+// and Ctrl-D on an empty line return io.EOF.
+func (e *lineEditor) readLine(prompt string) (string, error) {
+	if e.terminal {
+		if restore, ok := enableRawInput(os.Stdin); ok {
+			e.mu.Lock()
+			e.restore = restore
+			e.mu.Unlock()
+			defer e.restoreTerminal()
+			return e.edit(prompt)
+		}
+		e.terminal = false
+	}
+
+	fmt.Print(prompt)
+	line, err := e.in.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// restoreTerminal takes the terminal out of raw mode if a line is being
+// edited. The signal handler calls it so the shell isn't left raw on exit.
+func (e *lineEditor) restoreTerminal() {
+	e.mu.Lock()
+	restore := e.restore
+	e.restore = nil
+	e.mu.Unlock()
+	if restore != nil {
+		restore()
+	}
+}
+
+// edit runs the interactive editor. The terminal is in raw mode, so output
+// needs explicit carriage returns.
+func (e *lineEditor) edit(prompt string) (string, error) {
+	var buf []rune
+	histIdx := len(e.history)
+	draft := "" // what was typed before browsing history
+
+	redraw := func() { fmt.Printf("\r\x1b[K%s%s", prompt, string(buf)) }
+	redraw()
+
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			fmt.Print("\r\n")
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			fmt.Print("\r\n")
+			line := string(buf)
+			if strings.TrimSpace(line) != "" && (len(e.history) == 0 || e.history[len(e.history)-1] != line) {
+				e.history = append(e.history, line)
+			}
+			return line, nil
+		case 3: // Ctrl-C
+			fmt.Print("^C\r\n")
+			return "", io.EOF
+		case 4: // Ctrl-D
+			if len(buf) == 0 {
+				fmt.Print("\r\n")
+				return "", io.EOF
+			}
+		case 8, 127: // Backspace
+			if len(buf) > 0 {
+				buf = buf[:len(buf)-1]
+				redraw()
+			}
+		case '\t':
+			buf = e.complete(buf)
+			redraw()
+		case 27: // Escape sequence; only the up and down arrows are handled
+			if next, _, _ := e.in.ReadRune(); next != '[' && next != 'O' {
+				continue
+			}
+			arrow, _, _ := e.in.ReadRune()
+			switch {
+			case arrow == 'A' && histIdx > 0:
+				if histIdx == len(e.history) {
+					draft = string(buf)
+				}
+				histIdx--
+				buf = []rune(e.history[histIdx])
+			case arrow == 'B' && histIdx < len(e.history):
+				histIdx++
+				if histIdx == len(e.history) {
+					buf = []rune(draft)
+				} else {
+					buf = []rune(e.history[histIdx])
+				}
+			}
+			redraw()
+		default:
+			if r >= ' ' {
+				buf = append(buf, r)
+				fmt.Print(string(r))
+			}
+		}
+	}
+}
+
+// complete extends a partial command name. A unique match is filled in with a
+// trailing space; several matches are listed and their common prefix filled in.
+func (e *lineEditor) complete(buf []rune) []rune {
+	word := string(buf)
+	if strings.ContainsRune(word, ' ') {
+		return buf
+	}
+
+	var matches []string
+	for _, candidate := range e.words {
+		if strings.HasPrefix(candidate, word) {
+			matches = append(matches, candidate)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return buf
+	case 1:
+		return []rune(matches[0] + " ")
+	}
+
+	fmt.Printf("\r\n%s\r\n", strings.Join(matches, "  "))
+	prefix := matches[0]
+	for _, match := range matches[1:] {
+		for !strings.HasPrefix(match, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return []rune(prefix)
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"rez/internal/champselect"
+	"rez/internal/mockreplay"
+)
+
+// livePollInterval is how often -live checks the client for the local player's turn
+const livePollInterval = 500 * time.Millisecond
+
+// errNotFound is returned for 404s, which the session endpoint answers outside champ select
+var errNotFound = errors.New("not found")
+
+// defaultLockfile returns the lockfile of a standard install, or "" on other OSes
+func defaultLockfile() string {
+	switch runtime.GOOS {
+	case "windows":
+		return `C:\Riot Games\League of Legends\lockfile`
+	case "darwin":
+		return "/Applications/League of Legends.app/Contents/LoL/lockfile"
+	}
+	return ""
+}
+
+// liveClient talks to a running League client using the credentials in its lockfile
+type liveClient struct {
+	base     string
+	password string
+	http     *http.Client
+}
+
+func newLiveClient(lockfilePath string) (*liveClient, error) {
+	if lockfilePath == "" {
+		return nil, fmt.Errorf("no default lockfile on %s; pass -lockfile", runtime.GOOS)
+	}
+	data, err := os.ReadFile(lockfilePath)
+	if err != nil {
+		return nil, fmt.Errorf("read lockfile (is the client running?): %w", err)
+	}
+	// name:pid:port:password:protocol
+	parts := strings.Split(strings.TrimSpace(string(data)), ":")
+	if len(parts) < 5 {
+		return nil, fmt.Errorf("malformed lockfile %s", lockfilePath)
+	}
+	return &liveClient{
+		base:     fmt.Sprintf("%s://127.0.0.1:%s", parts[4], parts[2]),
+		password: parts[3],
+		http: &http.Client{
+			// The client serves a self-signed certificate
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+			Timeout:   10 * time.Second,
+		},
+	}, nil
+}
+
+// request is a champselect.Requester for the live client
+func (c *liveClient) request(method, endpoint string, payload interface{}) (map[string]interface{}, error) {
+	var body io.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequest(method, c.base+endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth("riot", c.password)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s %s: %w", method, endpoint, errNotFound)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("%s %s: %s: %s", method, endpoint, resp.Status, bytes.TrimSpace(data))
+	}
+
+	result := map[string]interface{}{}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// waitForTurn polls the client until the local player has an actionType action
+// in progress and returns its id. It refuses to act outside custom games, so a
+// replay can never pick or ban in a real match.
+func (c *liveClient) waitForTurn(actionType string) (int, error) {
+	inChampSelect, announced := false, false
+	for {
+		session, err := c.request("GET", champselect.SessionEndpoint, nil)
+		switch {
+		case errors.Is(err, errNotFound):
+			if inChampSelect {
+				return 0, errors.New("champ select ended before the replay finished")
+			}
+			if !announced {
+				fmt.Println("Waiting for champ select; start one in a custom lobby...")
+				announced = true
+			}
+		case err != nil:
+			return 0, err
+		default:
+			inChampSelect = true
+			if custom, _ := session["isCustomGame"].(bool); !custom {
+				return 0, errors.New("champ select is not a custom game; -live only acts in custom games")
+			}
+			if id, ok := champselect.LocalAction(session, actionType); ok {
+				return id, nil
+			}
+		}
+		time.Sleep(livePollInterval)
+	}
+}
+
+// runLive replays the capturing player's own picks and bans into a running
+// client through the champ-select action endpoints. The client only accepts
+// actions for the local player, so the other nine cells are not replayed.
+// Each champion is hovered as soon as the turn opens and locked in after
+// lockDelay.
+func runLive(steps []mockreplay.Step, lockfilePath string, lockDelay time.Duration) error {
+	actions := mockreplay.LocalActions(steps)
+	if len(actions) == 0 {
+		return errors.New("capture has no picks or bans by the local player (spectated or empty draft)")
+	}
+	client, err := newLiveClient(lockfilePath)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Replaying %d local actions into the client at %s\n", len(actions), client.base)
+
+	for _, action := range actions {
+		if action.ChampionID == 0 {
+			fmt.Printf("skipping %s; the client's timer will run out instead\n", action.Summary)
+			continue
+		}
+		actionID, err := client.waitForTurn(action.Type)
+		if err != nil {
+			return err
+		}
+		if err := champselect.Hover(client.request, actionID, action.ChampionID); err != nil {
+			return err
+		}
+		fmt.Printf("hovering champion %d for %s %d\n", action.ChampionID, action.Type, actionID)
+		time.Sleep(lockDelay)
+		if err := champselect.Complete(client.request, actionID); err != nil {
+			return err
+		}
+		fmt.Printf("locked in: %s\n", action.Summary)
+	}
+
+	fmt.Println("live replay finished")
+	return nil
+}
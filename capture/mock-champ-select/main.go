@@ -2,79 +2,66 @@ package main
 
 import (
 	"bufio"
-	"encoding/json"
+	"context"
 	"flag"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
 	"syscall"
 	"time"
 
-	"github.com/gorilla/websocket"
-
 	"rez/internal/mockreplay"
+	"rez/internal/mockserver"
 )
 
-type hub struct {
-	mu    sync.Mutex
-	conns map[*websocket.Conn]struct{}
-}
-
-func newHub() *hub {
-	return &hub{conns: make(map[*websocket.Conn]struct{})}
-}
-
-func (h *hub) add(conn *websocket.Conn) {
-	h.mu.Lock()
-	h.conns[conn] = struct{}{}
-	h.mu.Unlock()
-}
-
-func (h *hub) remove(conn *websocket.Conn) {
-	h.mu.Lock()
-	delete(h.conns, conn)
-	h.mu.Unlock()
-	conn.Close()
-}
-
-func (h *hub) broadcast(payload []byte) {
-	h.mu.Lock()
-	defer h.mu.Unlock()
-	for c := range h.conns {
-		if err := c.WriteMessage(websocket.TextMessage, payload); err != nil {
-			log.Printf("ws send failed, dropping client: %v", err)
-			c.Close()
-			delete(h.conns, c)
-		}
-	}
-}
-
-type state struct {
-	steps       []mockreplay.Step
-	current     int
-	hub         *hub
-	capturePath string
-	startedAt   string
-}
-
 func main() {
 	var (
 		capturePath string
 		addr        string
+		gameID      int64
+		wrapFrames  bool
+		follow      bool
+		playlistArg string
+		playlistGap time.Duration
+		encoding    string
+		interpolate time.Duration
+		maxGap      time.Duration
+		live        bool
+		lockfile    string
+		lockDelay   time.Duration
 	)
 
-	flag.StringVar(&capturePath, "capture", "", "path to a champ select capture file")
+	flag.StringVar(&capturePath, "capture", "", "path to a champ select capture file, or a directory of captures to stitch together")
 	flag.StringVar(&addr, "addr", "127.0.0.1:18080", "address for websocket + health server, e.g. 127.0.0.1:18080")
+	flag.Int64Var(&gameID, "game", 0, "only replay steps for this game id (for captures spanning several games)")
+	flag.BoolVar(&wrapFrames, "wrap", false, "wrap each broadcast as {index, total, raw} so test UIs can show replay position")
+	flag.BoolVar(&follow, "follow", false, "watch a capture file that is still being written and broadcast new events as they arrive")
+	flag.StringVar(&playlistArg, "playlist", "", "comma-separated captures (or a text file listing one per line) to play back to back")
+	flag.DurationVar(&playlistGap, "playlist-gap", 5*time.Second, "pause between captures in a playlist")
+	flag.StringVar(&encoding, "encoding", mockserver.EncodingJSON, "default frame encoding for /ws clients: json or msgpack (clients may override with ?encoding=)")
+	flag.DurationVar(&interpolate, "interpolate", 0, "during play, resend the current step this often with its timer counted down (e.g. 250ms); 0 disables")
+	flag.DurationVar(&maxGap, "max-gap", 10*time.Second, "when playing at captured timing, wait at most this long across pauses the capture flagged as gaps (0 = no limit)")
+	flag.BoolVar(&live, "live", false, "instead of serving, replay the capturing player's picks and bans into a running client's custom game")
+	flag.StringVar(&lockfile, "lockfile", defaultLockfile(), "with -live, the running client's lockfile")
+	flag.DurationVar(&lockDelay, "live-lock-delay", 2*time.Second, "with -live, how long each champion stays hovered before it is locked in")
 	flag.Parse()
 
-	if capturePath == "" {
+	if encoding != mockserver.EncodingJSON && encoding != mockserver.EncodingMsgpack {
+		fmt.Fprintf(os.Stderr, "unknown -encoding %q (want json or msgpack)\n", encoding)
+		os.Exit(2)
+	}
+
+	if live && (follow || playlistArg != "") {
+		fmt.Fprintln(os.Stderr, "-live replays a single capture; it can't be combined with -follow or -playlist")
+		os.Exit(2)
+	}
+
+	if capturePath == "" && playlistArg == "" {
 		selected, err := chooseCapture()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "no capture selected: %v\n", err)
@@ -83,192 +70,95 @@ func main() {
 		capturePath = selected
 	}
 
-	session, steps := loadStepsOrExit(capturePath)
-	st := &state{
-		steps:       steps,
-		current:     0,
-		hub:         newHub(),
-		capturePath: capturePath,
-		startedAt:   session.StartTime,
-	}
-
-	fmt.Printf("Loaded %d steps from %s (start: %s)\n", len(steps), capturePath, session.StartTime)
-	fmt.Printf("Websocket: ws://%s/ws | Health: http://%s/health\n", addr, addr)
-	fmt.Println("Commands: next, prev, jump <n>, send <n>, reset, inspect, current, quit, help")
-
-	upgrader := websocket.Upgrader{
-		CheckOrigin: func(r *http.Request) bool { return true },
+	if follow {
+		waitForCapture(capturePath)
 	}
 
-	http.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
-		conn, err := upgrader.Upgrade(w, r, nil)
+	var playlist []string
+	if playlistArg != "" {
+		paths, err := parsePlaylist(playlistArg)
 		if err != nil {
-			log.Printf("upgrade failed: %v", err)
-			return
+			fmt.Fprintf(os.Stderr, "invalid playlist: %v\n", err)
+			os.Exit(1)
 		}
-		st.hub.add(conn)
-		log.Printf("client connected (%d total)", len(st.hub.conns))
+		playlist = paths
+		capturePath = paths[0]
+	}
 
-		// push the current step immediately so new clients see state
-		if err := st.sendCurrent(conn); err != nil {
-			log.Printf("initial send failed: %v", err)
-			st.hub.remove(conn)
-			return
+	session, steps := loadStepsOrExit(capturePath)
+	if ids := mockreplay.GameIDs(steps); len(ids) > 1 && gameID == 0 {
+		fmt.Printf("Capture contains %d games %v; use -game to replay one\n", len(ids), ids)
+	}
+	if gameID != 0 {
+		steps = mockreplay.FilterByGame(steps, gameID)
+		if len(steps) == 0 {
+			fmt.Fprintf(os.Stderr, "capture has no steps for game %d\n", gameID)
+			os.Exit(1)
 		}
-
-		// keep connection alive; read loop just waits for closure
-		for {
-			if _, _, err := conn.ReadMessage(); err != nil {
-				break
-			}
+	}
+	if live {
+		if err := runLive(steps, lockfile, lockDelay); err != nil {
+			fmt.Fprintf(os.Stderr, "live replay: %v\n", err)
+			os.Exit(1)
 		}
-		st.hub.remove(conn)
-		log.Printf("client disconnected (%d total)", len(st.hub.conns))
-	})
+		return
+	}
 
-	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		current := st.steps[st.current]
-		payload := struct {
-			Steps       int    `json:"steps"`
-			Current     int    `json:"current"`
-			Summary     string `json:"summary"`
-			Capture     string `json:"capture"`
-			StartedAt   string `json:"started"`
-			CurrentSent string `json:"currentStepTimestamp"`
-		}{
-			Steps:       len(st.steps),
-			Current:     st.current,
-			Summary:     current.Summary,
-			Capture:     st.capturePath,
-			StartedAt:   st.startedAt,
-			CurrentSent: current.Timestamp.Format(time.RFC3339),
-		}
-		_ = json.NewEncoder(w).Encode(payload)
+	srv := mockserver.New(steps, mockserver.ServerOptions{
+		Addr:        addr,
+		CapturePath: capturePath,
+		StartedAt:   session.StartTime,
+		Metadata:    session.Metadata,
+		WrapFrames:  wrapFrames,
+		Encoding:    encoding,
+		Interpolate: interpolate,
+		MaxGap:      maxGap,
 	})
 
-	server := &http.Server{
-		Addr:              addr,
-		ReadHeaderTimeout: 5 * time.Second,
+	fmt.Printf("Loaded %d steps from %s (start: %s)\n", len(steps), capturePath, session.StartTime)
+	fmt.Printf("Websocket: ws://%s/ws | Control: ws://%s/control | Health: http://%s/health\n", addr, addr, addr)
+	fmt.Println("Commands: next, prev, jump <n>, send <n>, sendraw <json>, seekto <time>, play [ms], play-ramp <a> <b>, rewind <ms>, stop, reset, inspect, current, draft, skins, clients, quit, help")
+
+	if err := srv.Start(context.Background()); err != nil {
+		log.Fatalf("server error: %v", err)
 	}
 
-	go func() {
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("server error: %v", err)
-		}
-	}()
+	editor := newLineEditor(mockserver.Commands)
 
 	// Graceful shutdown on Ctrl+C
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-stop
+		editor.restoreTerminal()
 		log.Println("Shutting down...")
-		server.Close()
+		srv.Shutdown()
 		os.Exit(0)
 	}()
 
-	runRepl(st)
+	if follow {
+		go srv.Follow(capturePath, gameID)
+	}
+	if len(playlist) > 0 {
+		go srv.PlayPlaylist(playlist, playlistGap)
+	}
+
+	runRepl(srv, editor)
 }
 
-func runRepl(st *state) {
-	scanner := bufio.NewScanner(os.Stdin)
+func runRepl(srv *mockserver.Server, editor *lineEditor) {
 	for {
-		fmt.Print("> ")
-		if !scanner.Scan() {
-			break
-		}
-		line := strings.TrimSpace(scanner.Text())
-		switch {
-		case line == "", line == "help":
-			printHelp()
-		case line == "next":
-			st.advance(1, true)
-		case line == "prev":
-			st.advance(-1, true)
-		case strings.HasPrefix(line, "jump "):
-			st.jump(strings.TrimSpace(strings.TrimPrefix(line, "jump ")), true)
-		case strings.HasPrefix(line, "send "):
-			st.jump(strings.TrimSpace(strings.TrimPrefix(line, "send ")), true)
-		case line == "reset":
-			st.setIndex(0, false)
-		case line == "inspect" || line == "current":
-			st.inspect()
-		case line == "quit" || line == "exit":
+		line, err := editor.readLine("> ")
+		if err != nil || !srv.Exec(line) {
 			return
-		default:
-			fmt.Println("Unknown command, type 'help'")
 		}
 	}
 }
 
-func printHelp() {
-	fmt.Println("Commands:")
-	fmt.Println("  next            advance to the next step and broadcast")
-	fmt.Println("  prev            go back one step and broadcast")
-	fmt.Println("  jump <n>        jump to step n (0-based) and broadcast")
-	fmt.Println("  send <n>        alias for jump")
-	fmt.Println("  reset           reset index to 0 (no broadcast)")
-	fmt.Println("  inspect/current show current step summary")
-	fmt.Println("  quit            exit")
-}
-
-func (s *state) advance(delta int, broadcast bool) {
-	target := s.current + delta
-	s.setIndex(target, broadcast)
-}
-
-func (s *state) jump(raw string, broadcast bool) {
-	idx, err := strconv.Atoi(raw)
-	if err != nil {
-		fmt.Printf("invalid index %q: %v\n", raw, err)
-		return
-	}
-	s.setIndex(idx, broadcast)
-}
-
-func (s *state) setIndex(idx int, broadcast bool) {
-	if idx < 0 || idx >= len(s.steps) {
-		fmt.Printf("index out of range (0-%d)\n", len(s.steps)-1)
-		return
-	}
-	s.current = idx
-	if broadcast {
-		s.broadcastCurrent()
-	} else {
-		s.inspect()
-	}
-}
-
-func (s *state) broadcastCurrent() {
-	step := s.steps[s.current]
-	s.hub.broadcast(step.Raw)
-	fmt.Printf("sent step %d | %s\n", step.Index, step.Summary)
-}
-
-func (s *state) sendCurrent(conn *websocket.Conn) error {
-	step := s.steps[s.current]
-	return conn.WriteMessage(websocket.TextMessage, step.Raw)
-}
-
-func (s *state) inspect() {
-	step := s.steps[s.current]
-	fmt.Printf("step %d @ %s | %s\n", step.Index, step.Timestamp.Format(time.RFC3339), step.Summary)
-}
-
 func loadStepsOrExit(path string) (*mockreplay.CaptureSession, []mockreplay.Step) {
-	session, err := mockreplay.LoadCapture(path)
+	session, steps, err := mockserver.LoadSteps(path)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to load capture: %v\n", err)
-		os.Exit(1)
-	}
-	steps, err := mockreplay.BuildSteps(session)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to build steps: %v\n", err)
-		os.Exit(1)
-	}
-	if len(steps) == 0 {
-		fmt.Fprintln(os.Stderr, "capture has no steps")
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 	return session, steps
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// parsePlaylist accepts either comma-separated capture paths or the path of a
+// text file listing one capture per line (blank lines and # comments skipped).
+func parsePlaylist(arg string) ([]string, error) {
+	var paths []string
+	if !strings.Contains(arg, ",") && !strings.HasSuffix(strings.ToLower(arg), ".json") {
+		file, err := os.Open(arg)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line != "" && !strings.HasPrefix(line, "#") {
+				paths = append(paths, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, path := range strings.Split(arg, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no captures in %q", arg)
+	}
+	return paths, nil
+}
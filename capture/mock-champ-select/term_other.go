@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !windows
+
+package main
+
+import "os"
+
+// enableRawInput is unsupported here; the REPL falls back to plain line input.
+func enableRawInput(f *os.File) (restore func(), ok bool) {
+	return nil, false
+}
@@ -0,0 +1,30 @@
+//go:build linux || darwin
+
+package main
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// enableRawInput switches the terminal on f to raw input so the REPL can read
+// keys as they are pressed. Output processing is left on, so other goroutines
+// can keep printing. ok is false when f is not a terminal.
+func enableRawInput(f *os.File) (restore func(), ok bool) {
+	fd := int(f.Fd())
+	old, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return nil, false
+	}
+
+	raw := *old
+	raw.Iflag &^= unix.ICRNL | unix.IXON
+	raw.Lflag &^= unix.ECHO | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &raw); err != nil {
+		return nil, false
+	}
+	return func() { unix.IoctlSetTermios(fd, ioctlSetTermios, old) }, true
+}
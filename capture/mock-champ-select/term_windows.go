@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// enableRawInput switches the console on f to raw, VT-style input so the REPL
+// can read keys (including arrow escape sequences) as they are pressed. ok is
+// false when f is not a console.
+func enableRawInput(f *os.File) (restore func(), ok bool) {
+	in := windows.Handle(f.Fd())
+	var oldIn uint32
+	if err := windows.GetConsoleMode(in, &oldIn); err != nil {
+		return nil, false
+	}
+	rawIn := oldIn&^(windows.ENABLE_ECHO_INPUT|windows.ENABLE_LINE_INPUT|windows.ENABLE_PROCESSED_INPUT) | windows.ENABLE_VIRTUAL_TERMINAL_INPUT
+	if err := windows.SetConsoleMode(in, rawIn); err != nil {
+		return nil, false
+	}
+
+	// The editor redraws with ANSI escapes, which older consoles only honour when asked
+	out := windows.Handle(os.Stdout.Fd())
+	var oldOut uint32
+	outErr := windows.GetConsoleMode(out, &oldOut)
+	if outErr == nil {
+		windows.SetConsoleMode(out, oldOut|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING)
+	}
+
+	return func() {
+		windows.SetConsoleMode(in, oldIn)
+		if outErr == nil {
+			windows.SetConsoleMode(out, oldOut)
+		}
+	}, true
+}
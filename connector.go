@@ -2,17 +2,24 @@ package main
 
 import (
 	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -20,8 +27,24 @@ import (
 	"github.com/coder/websocket"
 	"github.com/fsnotify/fsnotify"
 	"github.com/shirou/gopsutil/v3/process"
+
+	"rez/internal/lcupath"
+)
+
+// WAMP 1.0 message types used by the LCU websocket
+const (
+	wampWelcome    = 0
+	wampCallResult = 3
+	wampCallError  = 4
+	wampSubscribe  = 5
+	wampEvent      = 8
 )
 
+const champSelectTopic = "OnJsonApiEvent_lol-champ-select_v1_session"
+
+// chatTopic is recorded by the capturer for champ-select chat and replayed by the mock server
+const chatTopic = "OnJsonApiEvent_lol-chat_v1_conversations"
+
 type ConnectionInfo struct {
 	Protocol string
 	Address  string
@@ -30,54 +53,109 @@ type ConnectionInfo struct {
 	Password string
 }
 
+// WebSocketURL returns the authenticated wss:// URL for the LCU websocket
+func (c ConnectionInfo) WebSocketURL() string {
+	return fmt.Sprintf("wss://%s:%s@%s:%s/", c.Username, c.Password, c.Address, c.Port)
+}
+
+// Validate checks that the lockfile produced something we can dial: an https
+// or wss protocol, a port in range and a non-empty password.
+func (c ConnectionInfo) Validate() error {
+	switch strings.ToLower(c.Protocol) {
+	case "https", "wss":
+	default:
+		return fmt.Errorf("invalid connection info: unsupported protocol %q", c.Protocol)
+	}
+	port, err := strconv.Atoi(c.Port)
+	if err != nil {
+		return fmt.Errorf("invalid connection info: port %q is not a number", c.Port)
+	}
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("invalid connection info: port %d out of range", port)
+	}
+	if c.Password == "" {
+		return fmt.Errorf("invalid connection info: empty password")
+	}
+	return nil
+}
+
+// FlexInt64 is an id that some client versions send as a number and others as
+// a string. It decodes either form so one field's type can't drop an event.
+type FlexInt64 int64
+
+func (f *FlexInt64) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	text := string(bytes.Trim(data, `"`))
+	if text == "" {
+		*f = 0
+		return nil
+	}
+	n, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		// Some ids come through as floats, e.g. 1.23e+10
+		fl, ferr := strconv.ParseFloat(text, 64)
+		if ferr != nil {
+			return fmt.Errorf("invalid id %s: %w", data, err)
+		}
+		n = int64(fl)
+	}
+	*f = FlexInt64(n)
+	return nil
+}
+
+// Action is one pick, ban or other champ-select turn.
+type Action struct {
+	ActorCellID  int    `json:"actorCellId"`
+	ChampionID   int    `json:"championId"`
+	Completed    bool   `json:"completed"`
+	IsAllyAction bool   `json:"isAllyAction"`
+	IsInProgress bool   `json:"isInProgress"`
+	Type         string `json:"type"`
+	PickTurn     int    `json:"pickTurn"`
+	Duration     int    `json:"duration"`
+	ID           int    `json:"id"`
+}
+
 type ChampSelectSession struct {
-	Actions [][]struct {
-		ActorCellID  int    `json:"actorCellId"`
-		ChampionID   int    `json:"championId"`
-		Completed    bool   `json:"completed"`
-		IsAllyAction bool   `json:"isAllyAction"`
-		IsInProgress bool   `json:"isInProgress"`
-		Type         string `json:"type"`
-		PickTurn     int    `json:"pickTurn"`
-		Duration     int    `json:"duration"`
-		ID           int    `json:"id"`
-	} `json:"actions"`
-	Bans struct {
+	Actions [][]Action `json:"actions"` // grouped by turn; simultaneous actions share a group
+	Bans    struct {
 		MyTeamBans    []int `json:"myTeamBans"`
 		TheirTeamBans []int `json:"theirTeamBans"`
 		NumBans       int   `json:"numBans"`
 	} `json:"bans"`
 	MyTeam []struct {
-		CellID             int    `json:"cellId"`
-		AssignedPosition   string `json:"assignedPosition"`
-		ChampionID         int    `json:"championId"`
-		ChampionPickIntent int    `json:"championPickIntent"`
-		SummonerID         int64  `json:"summonerId"`
-		GameName           string `json:"gameName"`
-		TagLine            string `json:"tagLine"`
-		Puuid              string `json:"puuid"`
-		Spell1ID           int    `json:"spell1Id"`
-		Spell2ID           int    `json:"spell2Id"`
-		SelectedSkinID     int    `json:"selectedSkinId"`
-		Team               int    `json:"team"`
-		WardSkinID         int    `json:"wardSkinId"`
-		NameVisibilityType string `json:"nameVisibilityType"`
+		CellID             int       `json:"cellId"`
+		AssignedPosition   string    `json:"assignedPosition"`
+		ChampionID         int       `json:"championId"`
+		ChampionPickIntent int       `json:"championPickIntent"`
+		SummonerID         FlexInt64 `json:"summonerId"`
+		GameName           string    `json:"gameName"`
+		TagLine            string    `json:"tagLine"`
+		Puuid              string    `json:"puuid"`
+		Spell1ID           int       `json:"spell1Id"`
+		Spell2ID           int       `json:"spell2Id"`
+		SelectedSkinID     int       `json:"selectedSkinId"`
+		Team               int       `json:"team"`
+		WardSkinID         int       `json:"wardSkinId"`
+		NameVisibilityType string    `json:"nameVisibilityType"`
 	} `json:"myTeam"`
 	TheirTeam []struct {
-		CellID             int    `json:"cellId"`
-		AssignedPosition   string `json:"assignedPosition"`
-		ChampionID         int    `json:"championId"`
-		ChampionPickIntent int    `json:"championPickIntent"`
-		SummonerID         int64  `json:"summonerId"`
-		GameName           string `json:"gameName"`
-		TagLine            string `json:"tagLine"`
-		Puuid              string `json:"puuid"`
-		Spell1ID           int    `json:"spell1Id"`
-		Spell2ID           int    `json:"spell2Id"`
-		SelectedSkinID     int    `json:"selectedSkinId"`
-		Team               int    `json:"team"`
-		WardSkinID         int    `json:"wardSkinId"`
-		NameVisibilityType string `json:"nameVisibilityType"`
+		CellID             int       `json:"cellId"`
+		AssignedPosition   string    `json:"assignedPosition"`
+		ChampionID         int       `json:"championId"`
+		ChampionPickIntent int       `json:"championPickIntent"`
+		SummonerID         FlexInt64 `json:"summonerId"`
+		GameName           string    `json:"gameName"`
+		TagLine            string    `json:"tagLine"`
+		Puuid              string    `json:"puuid"`
+		Spell1ID           int       `json:"spell1Id"`
+		Spell2ID           int       `json:"spell2Id"`
+		SelectedSkinID     int       `json:"selectedSkinId"`
+		Team               int       `json:"team"`
+		WardSkinID         int       `json:"wardSkinId"`
+		NameVisibilityType string    `json:"nameVisibilityType"`
 	} `json:"theirTeam"`
 	LocalPlayerCellID int `json:"localPlayerCellId"`
 	Timer             struct {
@@ -87,20 +165,114 @@ type ChampSelectSession struct {
 		TotalTimeInPhase        int    `json:"totalTimeInPhase"`
 		IsInfinite              bool   `json:"isInfinite"`
 	} `json:"timer"`
-	GameID             int64 `json:"gameId"`
-	QueueID            int   `json:"queueId"`
-	IsCustomGame       bool  `json:"isCustomGame"`
-	IsSpectating       bool  `json:"isSpectating"`
-	Counter            int   `json:"counter"`
-	AllowSkinSelection bool  `json:"allowSkinSelection"`
-	AllowRerolling     bool  `json:"allowRerolling"`
-	BenchEnabled       bool  `json:"benchEnabled"`
-	RerollsRemaining   int   `json:"rerollsRemaining"`
+	GameID             FlexInt64 `json:"gameId"`
+	QueueID            int       `json:"queueId"`
+	IsCustomGame       bool      `json:"isCustomGame"`
+	IsSpectating       bool      `json:"isSpectating"`
+	Counter            int       `json:"counter"`
+	AllowSkinSelection bool      `json:"allowSkinSelection"`
+	AllowRerolling     bool      `json:"allowRerolling"`
+	BenchEnabled       bool      `json:"benchEnabled"`
+	RerollsRemaining   int       `json:"rerollsRemaining"`
+	BenchChampions     []struct {
+		ChampionID int  `json:"championId"`
+		IsPriority bool `json:"isPriority"`
+	} `json:"benchChampions"`
+}
+
+// Spectating reports whether the local user is watching this champ select
+// rather than playing in it. Spectators have no cell of their own.
+func (s ChampSelectSession) Spectating() bool {
+	return s.IsSpectating || s.LocalPlayerCellID < 0
+}
+
+// isEmpty reports whether the session has no teams and no actions
+func (s ChampSelectSession) isEmpty() bool {
+	return len(s.MyTeam) == 0 && len(s.TheirTeam) == 0 && len(s.Actions) == 0
+}
+
+// FlattenActions returns every action in turn order.
+func FlattenActions(session ChampSelectSession) []Action {
+	var actions []Action
+	for _, group := range session.Actions {
+		actions = append(actions, group...)
+	}
+	return actions
+}
+
+// CurrentAction returns the local player's in-progress, uncompleted action, if
+// it is their turn to pick or ban.
+func CurrentAction(session ChampSelectSession) (Action, bool) {
+	if session.Spectating() {
+		return Action{}, false
+	}
+	for _, action := range FlattenActions(session) {
+		if action.ActorCellID == session.LocalPlayerCellID && action.IsInProgress && !action.Completed {
+			return action, true
+		}
+	}
+	return Action{}, false
+}
+
+// LocalPickTurn reports how many pick turns remain before the local player's
+// pick. Each action group is one turn, and simultaneous picks share a group.
+// turn is 0 when the local player picks in the current turn and -1 when they
+// have no pick left. onClock is true while their pick is in progress.
+func LocalPickTurn(session ChampSelectSession) (turn int, onClock bool) {
+	if session.Spectating() {
+		return -1, false
+	}
+	ahead := 0
+	for _, group := range session.Actions {
+		pendingPick := false
+		for _, action := range group {
+			if action.Type != "pick" || action.Completed {
+				continue
+			}
+			if action.ActorCellID == session.LocalPlayerCellID {
+				return ahead, action.IsInProgress
+			}
+			pendingPick = true
+		}
+		if pendingPick {
+			ahead++
+		}
+	}
+	return -1, false
+}
+
+// WatchMode selects how the connector notices the lockfile appearing, changing
+// and going away.
+type WatchMode int
+
+const (
+	WatchFSNotify WatchMode = iota // filesystem notifications (default)
+	WatchPoll                      // stat the lockfile every second; for network shares and WSL
+	WatchAuto                      // fsnotify, polling any directory it cannot watch
+)
+
+// ParseWatchMode accepts "fsnotify", "poll" or "auto" (case-insensitive); empty means fsnotify.
+func ParseWatchMode(s string) (WatchMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "fsnotify":
+		return WatchFSNotify, nil
+	case "poll":
+		return WatchPoll, nil
+	case "auto":
+		return WatchAuto, nil
+	}
+	return WatchFSNotify, fmt.Errorf("unknown watch mode %q (want fsnotify, poll or auto)", s)
 }
 
 type LCUConnector struct {
 	dirPath            string
+	WatchMode          WatchMode // How lockfiles are watched; set before Start
 	lockfileWatcher    *fsnotify.Watcher
+	watchedDirs        map[string]bool
+	pollDirs           map[string]time.Time // polled dir -> lockfile mtime last seen (zero if absent), guarded by mu
+	pollStop           chan struct{}        // closes to stop the poller; nil when not polling, guarded by mu
+	lastConnected      *ConnectionInfo      // last connection announced on OnConnect; nil once disconnected, guarded by mu
+	badFields          map[string]bool      // mistyped session fields already logged, indices elided; guarded by mu
 	processTicker      *time.Ticker
 	stopCh             chan struct{}
 	mu                 sync.Mutex
@@ -108,6 +280,15 @@ type LCUConnector struct {
 	OnDisconnect       chan struct{}
 	OnChampSelect      chan ChampSelectSession
 	OnChampSelectEnded chan struct{}
+	OnReconnecting     chan int // Attempt number, starting at 1
+	OnReconnected      chan ConnectionInfo
+	OnSubscribed       chan string
+	OnError            chan error
+	rawSink            func([]any)    // Optional tee for raw champ-select payloads
+	Logger             *log.Logger    // Connection diagnostics; replace or discard after New
+	SendTimeout        time.Duration  // How long a busy consumer gets before an event is dropped; set before Start
+	lastEventAt        time.Time      // guarded by mu
+	lastSession        map[string]any // last full session data, for merging partial Updates; guarded by mu
 	wsConn             *websocket.Conn
 	wsContext          context.Context
 	wsCancel           context.CancelFunc
@@ -120,7 +301,15 @@ func New(executablePath string) *LCUConnector {
 		OnConnect:          make(chan ConnectionInfo),
 		OnDisconnect:       make(chan struct{}),
 		OnChampSelect:      make(chan ChampSelectSession),
+		watchedDirs:        make(map[string]bool),
+		pollDirs:           make(map[string]time.Time),
 		OnChampSelectEnded: make(chan struct{}),
+		OnReconnecting:     make(chan int),
+		OnReconnected:      make(chan ConnectionInfo),
+		OnSubscribed:       make(chan string),
+		OnError:            make(chan error),
+		Logger:             log.New(os.Stderr, "[lcu] ", log.LstdFlags|log.Lmicroseconds),
+		SendTimeout:        defaultSendTimeout,
 		stopCh:             make(chan struct{}),
 	}
 	if executablePath != "" {
@@ -130,10 +319,16 @@ func New(executablePath string) *LCUConnector {
 }
 
 func (l *LCUConnector) Start() {
-	if IsValidLCUPath(l.dirPath) {
-		l.initLockfileWatcher()
+	if lcupath.IsValid(l.dirPath) {
+		l.initLockfileWatcher(l.dirPath)
 		return
 	}
+
+	// Watch well-known install locations while the process watcher looks for
+	// the actual one; whichever yields a lockfile first wins.
+	if dirs := existingDirs(defaultLockfileDirs()); len(dirs) > 0 {
+		l.initLockfileWatcher(dirs...)
+	}
 	l.initProcessWatcher()
 }
 
@@ -144,8 +339,54 @@ func (l *LCUConnector) Stop() {
 	close(l.stopCh)
 }
 
+// SetRawSink registers fn to receive every raw champ-select payload, including
+// Deletes, before it is decoded. Must be called before Start.
+func (l *LCUConnector) SetRawSink(fn func([]any)) {
+	l.rawSink = fn
+}
+
+// LastChampSelectEventAt returns when the last champ-select event arrived, or
+// the zero time if none has. A connected socket that stays silent for long
+// usually means the subscription was lost.
+func (l *LCUConnector) LastChampSelectEventAt() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lastEventAt
+}
+
+// InstallDir returns the League install directory the connector is watching,
+// or "" before one has been found.
+func (l *LCUConnector) InstallDir() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.dirPath
+}
+
 // -------- PRIVATE METHODS --------
 
+// infof writes an info-level connection log line
+func (l *LCUConnector) infof(format string, args ...any) {
+	if l.Logger != nil {
+		l.Logger.Printf("INFO "+format, args...)
+	}
+}
+
+// debugf logs frames that were dropped for having an unexpected shape
+func (l *LCUConnector) debugf(format string, args ...any) {
+	if l.Logger != nil {
+		l.Logger.Printf("DEBUG "+format, args...)
+	}
+}
+
+// truncateFrame shortens a raw frame for logging
+func truncateFrame(data []byte) string {
+	const max = 200
+	if len(data) <= max {
+		return string(data)
+	}
+	return string(data[:max]) + "..."
+}
+
 func (l *LCUConnector) initProcessWatcher() {
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -154,15 +395,22 @@ func (l *LCUConnector) initProcessWatcher() {
 		return
 	}
 	l.processTicker = time.NewTicker(time.Second)
+	started := time.Now()
+	l.infof("looking for the LeagueClientUx process")
 	go func() {
 		for {
 			select {
 			case <-l.processTicker.C:
 				path, _ := GetLCUPathFromProcess()
+				source := "process"
+				if path == "" {
+					path, _ = GetLCUPathFromRiotClient()
+					source = "Riot Client"
+				}
 				if path != "" {
-					l.dirPath = path
+					l.infof("install found via %s at %s after %s", source, path, time.Since(started).Round(time.Millisecond))
 					l.clearProcessWatcher()
-					l.initLockfileWatcher()
+					l.initLockfileWatcher(path)
 					return
 				}
 			case <-l.stopCh:
@@ -181,44 +429,156 @@ func (l *LCUConnector) clearProcessWatcher() {
 	}
 }
 
-func (l *LCUConnector) initLockfileWatcher() {
-	if l.lockfileWatcher != nil {
-		return
+// initLockfileWatcher watches each directory for a lockfile, creating the
+// watcher on first use and adding directories to it on later calls. In
+// WatchPoll mode, and for directories fsnotify rejects in WatchAuto mode, the
+// directory is polled instead.
+func (l *LCUConnector) initLockfileWatcher(dirs ...string) {
+	l.mu.Lock()
+	watcher := l.lockfileWatcher
+	if watcher == nil && l.WatchMode != WatchPoll {
+		var err error
+		watcher, err = fsnotify.NewWatcher()
+		if err != nil {
+			if l.WatchMode != WatchAuto {
+				l.mu.Unlock()
+				return
+			}
+			l.infof("fsnotify unavailable (%v); polling for lockfiles", err)
+			watcher = nil
+		} else {
+			l.lockfileWatcher = watcher
+			go l.watchLockfiles(watcher)
+		}
 	}
 
-	lockfilePath := filepath.Join(l.dirPath, "lockfile")
-	watcher, err := fsnotify.NewWatcher()
-	if err != nil {
-		return
+	var added []string
+	for _, dir := range dirs {
+		if l.watchedDirs[dir] {
+			continue
+		}
+		if watcher != nil {
+			// Start watching directory
+			err := watcher.Add(dir)
+			if err != nil && l.WatchMode != WatchAuto {
+				l.infof("cannot watch %s: %v", dir, err)
+				continue
+			}
+			if err == nil {
+				l.infof("watching %s for a lockfile", dir)
+				l.watchedDirs[dir] = true
+				added = append(added, dir)
+				continue
+			}
+			l.infof("cannot watch %s (%v); polling it instead", dir, err)
+		}
+		l.pollDir(dir)
+		l.watchedDirs[dir] = true
+		added = append(added, dir)
 	}
-	l.lockfileWatcher = watcher
-	go func() {
-		defer watcher.Close()
-		for {
-			select {
-			case event, ok := <-watcher.Events:
-				if !ok {
-					return
-				}
-				if event.Op&fsnotify.Create != 0 || event.Op&fsnotify.Write != 0 {
-					l.onFileCreated(lockfilePath)
-				} else if event.Op&fsnotify.Remove != 0 {
-					l.onFileRemoved()
-				}
-			case <-l.stopCh:
-				return
+	l.mu.Unlock()
+
+	// If a lockfile already exists, trigger connect
+	for _, dir := range added {
+		lockfilePath := filepath.Join(dir, "lockfile")
+		if _, err := os.Stat(lockfilePath); err == nil {
+			l.onFileCreated(lockfilePath)
+			return
+		}
+	}
+}
+
+// pollDir adds dir to the poller, starting it on first use. The current
+// lockfile state is recorded so an existing lockfile isn't reported twice.
+// Callers must hold l.mu.
+func (l *LCUConnector) pollDir(dir string) {
+	var mtime time.Time
+	if info, err := os.Stat(filepath.Join(dir, "lockfile")); err == nil {
+		mtime = info.ModTime()
+	}
+	l.pollDirs[dir] = mtime
+	l.infof("polling %s for a lockfile every %s", dir, lockfilePollInterval)
+
+	if l.pollStop == nil {
+		l.pollStop = make(chan struct{})
+		go l.pollLockfiles(l.pollStop)
+	}
+}
+
+// pollLockfiles stats each polled lockfile and reports creation, modification
+// and removal the same way watchLockfiles does for fsnotify events.
+func (l *LCUConnector) pollLockfiles(stop chan struct{}) {
+	ticker := time.NewTicker(lockfilePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		case <-l.stopCh:
+			return
+		}
+
+		var created, removed []string
+		l.mu.Lock()
+		for dir, seen := range l.pollDirs {
+			path := filepath.Join(dir, "lockfile")
+			var mtime time.Time
+			if info, err := os.Stat(path); err == nil {
+				mtime = info.ModTime()
+			}
+			switch {
+			case mtime.Equal(seen):
+			case mtime.IsZero():
+				removed = append(removed, path)
+			default:
+				created = append(created, path)
 			}
+			l.pollDirs[dir] = mtime
 		}
-	}()
-	// Start watching directory
-	watcher.Add(l.dirPath)
+		l.mu.Unlock()
 
-	// If already exists, trigger connect
-	if _, err := os.Stat(lockfilePath); err == nil {
-		l.onFileCreated(lockfilePath)
+		for _, path := range removed {
+			if l.isActiveLockfile(path) {
+				l.onFileRemoved()
+			}
+		}
+		for _, path := range created {
+			l.onFileCreated(path)
+		}
+	}
+}
+
+func (l *LCUConnector) watchLockfiles(watcher *fsnotify.Watcher) {
+	defer watcher.Close()
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Base(event.Name) != "lockfile" {
+				continue
+			}
+			if event.Op&fsnotify.Create != 0 || event.Op&fsnotify.Write != 0 {
+				l.onFileCreated(event.Name)
+			} else if event.Op&fsnotify.Remove != 0 && l.isActiveLockfile(event.Name) {
+				l.onFileRemoved()
+			}
+		case <-l.stopCh:
+			return
+		}
 	}
 }
 
+// isActiveLockfile reports whether path is the lockfile we are connected through
+func (l *LCUConnector) isActiveLockfile(path string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return filepath.Join(l.dirPath, "lockfile") == path
+}
+
 func (l *LCUConnector) clearLockfileWatcher() {
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -226,57 +586,76 @@ func (l *LCUConnector) clearLockfileWatcher() {
 		l.lockfileWatcher.Close()
 		l.lockfileWatcher = nil
 	}
+	if l.pollStop != nil {
+		close(l.pollStop)
+		l.pollStop = nil
+		l.pollDirs = make(map[string]time.Time)
+	}
+	l.watchedDirs = make(map[string]bool)
 }
 
 func (l *LCUConnector) onFileCreated(lockfilePath string) {
-	data, err := os.ReadFile(lockfilePath)
+	l.infof("lockfile detected at %s", lockfilePath)
+	info, err := readLockfile(lockfilePath)
 	if err != nil {
+		l.infof("lockfile unreadable: %v", err)
 		return
 	}
-	// Lockfile format: <name>:<PID>:<port>:<password>:<protocol>
-	parts := strings.Split(strings.TrimSpace(string(data)), ":")
-	if len(parts) < 5 {
+	l.infof("lockfile parsed: %s on port %s", info.Protocol, info.Port)
+	if err := info.Validate(); err != nil {
+		l.infof("lockfile rejected: %v", err)
+		deliver(l, l.OnError, fmt.Errorf("%s: %w", lockfilePath, err))
 		return
 	}
-	info := ConnectionInfo{
-		Protocol: parts[4],
-		Address:  "127.0.0.1",
-		Port:     parts[2],
-		Username: "riot",
-		Password: parts[3],
+
+	l.mu.Lock()
+	if l.wsConn != nil && filepath.Join(l.dirPath, "lockfile") != lockfilePath {
+		// Already connected through another install's lockfile
+		l.mu.Unlock()
+		return
 	}
+	l.dirPath = filepath.Dir(lockfilePath)
+	l.mu.Unlock()
 
 	// Initialize WebSocket connection
-	l.initWebSocket(info)
+	if err := l.dialInitial(info); err != nil {
+		l.infof("websocket connect failed: %v", err)
+	}
 
-	select {
-	case l.OnConnect <- info:
-	default:
+	// The initial stat and fsnotify's Create/Write events all land here for the
+	// same lockfile; announce each distinct connection once
+	l.mu.Lock()
+	duplicate := l.lastConnected != nil && *l.lastConnected == info
+	l.lastConnected = &info
+	l.mu.Unlock()
+	if duplicate {
+		l.debugf("lockfile unchanged; not announcing the connection again")
+		return
 	}
+
+	deliver(l, l.OnConnect, info)
 }
 
 func (l *LCUConnector) onFileRemoved() {
 	l.clearWebSocket()
-	select {
-	case l.OnDisconnect <- struct{}{}:
-	default:
-	}
+	l.forgetConnection()
+	deliver(l, l.OnDisconnect, struct{}{})
 }
 
-func (l *LCUConnector) initWebSocket(info ConnectionInfo) {
+func (l *LCUConnector) initWebSocket(info ConnectionInfo) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
 	// Clear existing connection if any
 	if l.wsConn != nil {
-		return
+		return nil
 	}
 
 	// Create context for WebSocket
-	l.wsContext, l.wsCancel = context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
 
 	// Build WebSocket URL
-	wsURL := fmt.Sprintf("wss://%s:%s@%s:%s/", info.Username, info.Password, info.Address, info.Port)
+	wsURL := info.WebSocketURL()
 
 	// Configure WebSocket dialer with TLS config
 	dialer := websocket.DialOptions{
@@ -285,18 +664,109 @@ func (l *LCUConnector) initWebSocket(info ConnectionInfo) {
 				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 			},
 		},
+		// Offer permessage-deflate; if negotiated the library inflates frames for us
+		CompressionMode: websocket.CompressionContextTakeover,
 	}
 
 	// Connect to WebSocket
-	conn, _, err := websocket.Dial(l.wsContext, wsURL, &dialer)
+	dialStarted := time.Now()
+	l.infof("dialing websocket at %s:%s", info.Address, info.Port)
+	conn, _, err := websocket.Dial(ctx, wsURL, &dialer)
 	if err != nil {
-		return
+		l.infof("websocket dial failed after %s", time.Since(dialStarted).Round(time.Millisecond))
+		cancel()
+		return err
 	}
+	l.infof("websocket connected in %s", time.Since(dialStarted).Round(time.Millisecond))
 
 	l.wsConn = conn
+	l.wsContext, l.wsCancel = ctx, cancel
 
 	// Start WebSocket listener
-	go l.handleWebSocket()
+	go l.handleWebSocket(ctx, conn)
+	return nil
+}
+
+// dialInitial connects the websocket for a freshly detected lockfile. The LCU
+// writes the lockfile before its websocket endpoint is listening, so the first
+// dials are retried with a short backoff.
+func (l *LCUConnector) dialInitial(info ConnectionInfo) error {
+	delay := initialDialBaseDelay
+	var err error
+	for attempt := 1; attempt <= initialDialAttempts; attempt++ {
+		if err = l.initWebSocket(info); err == nil {
+			return nil
+		}
+		if attempt == initialDialAttempts {
+			break
+		}
+		l.infof("websocket not ready (attempt %d/%d), retrying in %s", attempt, initialDialAttempts, delay)
+
+		select {
+		case <-time.After(delay):
+		case <-l.stopCh:
+			return err
+		}
+
+		delay *= 2
+		if delay > reconnectMaxDelay {
+			delay = reconnectMaxDelay
+		}
+	}
+	return err
+}
+
+// reconnect re-dials the websocket after an unexpected drop, re-reading the
+// lockfile each attempt since the client may restart on a new port.
+func (l *LCUConnector) reconnect() {
+	l.mu.Lock()
+	lockfilePath := filepath.Join(l.dirPath, "lockfile")
+	l.mu.Unlock()
+	delay := reconnectBaseDelay
+
+	for attempt := 1; attempt <= maxReconnectAttempts; attempt++ {
+		deliver(l, l.OnReconnecting, attempt)
+
+		select {
+		case <-time.After(delay):
+		case <-l.stopCh:
+			return
+		}
+
+		info, err := readLockfile(lockfilePath)
+		if err != nil {
+			// Lockfile gone: the client shut down and the watcher reports the disconnect
+			return
+		}
+		if err := info.Validate(); err != nil {
+			// Probably caught mid-write; the next attempt re-reads it
+			l.infof("lockfile rejected: %v", err)
+			continue
+		}
+		if err := l.initWebSocket(info); err == nil {
+			l.mu.Lock()
+			l.lastConnected = &info
+			l.mu.Unlock()
+			deliver(l, l.OnReconnected, info)
+			return
+		}
+
+		delay *= 2
+		if delay > reconnectMaxDelay {
+			delay = reconnectMaxDelay
+		}
+	}
+
+	l.forgetConnection()
+	deliver(l, l.OnDisconnect, struct{}{})
+}
+
+// forgetConnection clears the last announced connection so the next lockfile
+// read fires OnConnect again
+func (l *LCUConnector) forgetConnection() {
+	l.mu.Lock()
+	l.lastConnected = nil
+	l.mu.Unlock()
 }
 
 func (l *LCUConnector) clearWebSocket() {
@@ -316,43 +786,201 @@ func (l *LCUConnector) clearWebSocket() {
 	l.wsContext = nil
 }
 
-func (l *LCUConnector) handleWebSocket() {
+// mergeSessionEvent applies a champ-select event to the last known session. The
+// client sometimes sends Updates carrying only the fields that changed; merging
+// keeps the rest instead of decoding them as empty. Create replaces the session
+// and Delete clears it. The returned event is a copy; the raw payload is untouched.
+func (l *LCUConnector) mergeSessionEvent(event map[string]any) map[string]any {
+	eventType, _ := event["eventType"].(string)
+	data, _ := event["data"].(map[string]any)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch {
+	case eventType == "Delete":
+		l.lastSession = nil
+		return event
+	case data == nil:
+		return event
+	case eventType == "Update" && l.lastSession != nil:
+		data = mergeJSONObjects(l.lastSession, data)
+	}
+	l.lastSession = data
+
+	merged := make(map[string]any, len(event))
+	for key, value := range event {
+		merged[key] = value
+	}
+	merged["data"] = data
+	return merged
+}
+
+// mergeJSONObjects returns base overlaid with patch. Nested objects are merged
+// key by key; arrays and scalars in patch replace the base value outright, since
+// array entries can't be matched up reliably. Neither argument is modified.
+func mergeJSONObjects(base, patch map[string]any) map[string]any {
+	merged := make(map[string]any, len(base))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range patch {
+		baseObj, baseIsObj := merged[key].(map[string]any)
+		patchObj, patchIsObj := value.(map[string]any)
+		if baseIsObj && patchIsObj {
+			merged[key] = mergeJSONObjects(baseObj, patchObj)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// decodeBinaryMessage turns a binary frame back into JSON text. The LCU only
+// sends text, so binary frames are assumed to be compressed payloads.
+func decodeBinaryMessage(data []byte) ([]byte, error) {
+	if json.Valid(data) {
+		return data, nil
+	}
+
+	readers := []func(io.Reader) (io.ReadCloser, error){
+		func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+		func(r io.Reader) (io.ReadCloser, error) { return zlib.NewReader(r) },
+		func(r io.Reader) (io.ReadCloser, error) { return flate.NewReader(r), nil },
+	}
+
+	for _, newReader := range readers {
+		r, err := newReader(bytes.NewReader(data))
+		if err != nil {
+			continue
+		}
+		decoded, err := io.ReadAll(r)
+		r.Close()
+		if err == nil && json.Valid(decoded) {
+			return decoded, nil
+		}
+	}
+
+	return nil, errors.New("not JSON and could not be decompressed")
+}
+
+// dropWebSocket forgets a connection that died underneath us so it can be re-dialed
+func (l *LCUConnector) dropWebSocket(conn *websocket.Conn) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.wsConn != conn {
+		return
+	}
+	if l.wsCancel != nil {
+		l.wsCancel()
+		l.wsCancel = nil
+	}
+	l.wsConn.CloseNow()
+	l.wsConn = nil
+	l.wsContext = nil
+}
+
+func (l *LCUConnector) handleWebSocket(ctx context.Context, conn *websocket.Conn) {
 	// Subscribe to champ select events
-	subMsg := []any{5, "OnJsonApiEvent_lol-champ-select_v1_session"}
+	subMsg := []any{wampSubscribe, champSelectTopic}
 	msgBytes, err := json.Marshal(subMsg)
 	if err != nil {
 		return
 	}
 
-	if err := l.wsConn.Write(l.wsContext, websocket.MessageText, msgBytes); err != nil {
+	if err := conn.Write(ctx, websocket.MessageText, msgBytes); err != nil {
 		return
 	}
 
+	// A new connection starts from a clean slate; the next Create or Update is the baseline
+	l.mu.Lock()
+	l.lastSession = nil
+	l.mu.Unlock()
+
 	// Read messages in a loop
 	for {
 		select {
-		case <-l.wsContext.Done():
+		case <-ctx.Done():
 			return
 		default:
-			_, data, err := l.wsConn.Read(l.wsContext)
+			msgType, data, err := conn.Read(ctx)
 			if err != nil {
+				// A cancelled context means we closed it ourselves
+				if ctx.Err() == nil {
+					l.dropWebSocket(conn)
+					go l.reconnect()
+				}
 				return
 			}
 
-			// Parse WebSocket message
+			if msgType == websocket.MessageBinary {
+				decoded, err := decodeBinaryMessage(data)
+				if err != nil {
+					deliver(l, l.OnError, fmt.Errorf("unexpected binary websocket message (%d bytes): %v", len(data), err))
+					continue
+				}
+				data = decoded
+			}
+
+			// Parse WebSocket message; every WAMP frame is an array led by its opcode
 			var payload []any
-			if err := json.Unmarshal(data, &payload); err != nil || len(payload) < 3 {
+			if err := json.Unmarshal(data, &payload); err != nil || len(payload) == 0 {
+				if len(bytes.TrimSpace(data)) > 0 {
+					l.debugf("ignoring non-WAMP frame: %s", truncateFrame(data))
+				}
 				continue
 			}
 
-			// Check if it's the event we subscribed to
+			opcode, ok := payload[0].(float64)
+			if !ok {
+				l.debugf("ignoring frame without a numeric opcode: %s", truncateFrame(data))
+				continue
+			}
+			switch int(opcode) {
+			case wampWelcome:
+				continue
+			case wampCallResult:
+				// Subscription acknowledged
+				deliver(l, l.OnSubscribed, champSelectTopic)
+				continue
+			case wampCallError:
+				deliver(l, l.OnError, fmt.Errorf("subscription to %s rejected: %v", champSelectTopic, payload[1:]))
+				continue
+			case wampEvent:
+			default:
+				l.debugf("ignoring frame with opcode %d: %s", int(opcode), truncateFrame(data))
+				continue
+			}
+
+			// Events are [8, topic, data]
+			if len(payload) < 3 {
+				l.debugf("ignoring short event frame: %s", truncateFrame(data))
+				continue
+			}
 			eventType, ok := payload[1].(string)
-			if !ok || eventType != "OnJsonApiEvent_lol-champ-select_v1_session" {
+			if !ok {
+				l.debugf("ignoring event without a topic name: %s", truncateFrame(data))
 				continue
 			}
+			if eventType != champSelectTopic {
+				continue
+			}
+			event, ok := payload[2].(map[string]any)
+			if !ok {
+				l.debugf("ignoring %s event whose data is not an object: %s", eventType, truncateFrame(data))
+				continue
+			}
+
+			l.mu.Lock()
+			l.lastEventAt = time.Now()
+			l.mu.Unlock()
 
-			// Parse the event data
-			body, err := json.Marshal(payload[2])
+			if l.rawSink != nil {
+				l.rawSink(payload)
+			}
+
+			// Parse the event data, filling in whatever a partial Update left out
+			body, err := json.Marshal(l.mergeSessionEvent(event))
 			if err != nil {
 				continue
 			}
@@ -361,31 +989,196 @@ func (l *LCUConnector) handleWebSocket() {
 				EventType string             `json:"eventType"`
 				Data      ChampSelectSession `json:"data"`
 			}
-			if err := json.Unmarshal(body, &champData); err != nil {
+			if err := l.decodeChampSelectEvent(body, &champData); err != nil {
+				l.debugf("could not decode champ select event: %v", err)
 				continue
 			}
 
 			// Handle different event types
 			if champData.EventType == "Delete" {
 				// Champion select ended
-				select {
-				case l.OnChampSelectEnded <- struct{}{}:
-				default:
-				}
+				deliver(l, l.OnChampSelectEnded, struct{}{})
 				continue
 			}
 
-			// Emit champ select data for Create and Update events
-			select {
-			case l.OnChampSelect <- champData.Data:
-			default:
+			// Skip effectively empty sessions sent mid-transition
+			if champData.Data.isEmpty() {
+				continue
 			}
+
+			// Emit champ select data for Create and Update events
+			deliver(l, l.OnChampSelect, champData.Data)
 		}
 	}
 }
 
 // -------- HELPER FUNCTIONS --------
 
+// deliver sends v on ch. A consumer that isn't ready gets up to l.SendTimeout
+// to take it, so a momentarily busy one doesn't miss the event, but a stalled
+// one can't block the connector; after that the event is dropped. It reports
+// whether v was delivered.
+func deliver[T any](l *LCUConnector, ch chan T, v T) bool {
+	select {
+	case ch <- v:
+		return true
+	default:
+	}
+	if l.SendTimeout <= 0 {
+		return false
+	}
+
+	timer := time.NewTimer(l.SendTimeout)
+	defer timer.Stop()
+	select {
+	case ch <- v:
+		return true
+	case <-timer.C:
+		l.debugf("consumer busy for %s; dropped %T event", l.SendTimeout, v)
+		return false
+	case <-l.stopCh:
+		return false
+	}
+}
+
+// decodeChampSelectEvent unmarshals body into v, tolerating fields whose JSON
+// type doesn't match the struct (e.g. a championId sent as a string or float).
+// Each bad field is left zero so the rest of the session still arrives, and
+// logged the first time it is seen; only malformed JSON is an error.
+func (l *LCUConnector) decodeChampSelectEvent(body []byte, v any) error {
+	err := json.Unmarshal(body, v)
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		return err
+	}
+
+	// encoding/json keeps decoding past a type mismatch but reports only the
+	// first one, so walk the document to log every field that was dropped.
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return err
+	}
+	for _, field := range mismatchedFields(doc, reflect.TypeOf(v).Elem(), "") {
+		// A type change is usually permanent, so report each field once rather
+		// than on every update for every player
+		key := fieldIndexPattern.ReplaceAllString(field, "[]")
+		l.mu.Lock()
+		seen := l.badFields[key]
+		if !seen {
+			if l.badFields == nil {
+				l.badFields = make(map[string]bool)
+			}
+			l.badFields[key] = true
+		}
+		l.mu.Unlock()
+		if !seen {
+			l.debugf("champ select field %s has an unexpected type; leaving it zero", field)
+		}
+	}
+	return nil
+}
+
+// fieldIndexPattern matches the slice indices in a mismatchedFields path
+var fieldIndexPattern = regexp.MustCompile(`\[\d+\]`)
+
+// mismatchedFields returns the dotted paths in doc whose JSON type can't be
+// decoded into the matching field of t
+func mismatchedFields(doc interface{}, t reflect.Type, path string) []string {
+	if doc == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()) {
+		return nil // custom decoders such as FlexInt64 report their own errors
+	}
+
+	var bad []string
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return []string{path}
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			if value, ok := obj[name]; ok {
+				bad = append(bad, mismatchedFields(value, field.Type, joinFieldPath(path, name))...)
+			}
+		}
+	case reflect.Slice:
+		items, ok := doc.([]interface{})
+		if !ok {
+			return []string{path}
+		}
+		for i, item := range items {
+			bad = append(bad, mismatchedFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, ok := doc.(float64); !ok || n != float64(int64(n)) {
+			return []string{path}
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, ok := doc.(float64); !ok {
+			return []string{path}
+		}
+	case reflect.String:
+		if _, ok := doc.(string); !ok {
+			return []string{path}
+		}
+	case reflect.Bool:
+		if _, ok := doc.(bool); !ok {
+			return []string{path}
+		}
+	}
+	return bad
+}
+
+func joinFieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+const (
+	maxReconnectAttempts = 5
+	reconnectBaseDelay   = time.Second
+	reconnectMaxDelay    = 10 * time.Second
+
+	initialDialAttempts  = 6
+	initialDialBaseDelay = 250 * time.Millisecond
+
+	lockfilePollInterval = time.Second
+
+	defaultSendTimeout = 50 * time.Millisecond
+)
+
+// readLockfile parses the LCU lockfile into connection details.
+// Lockfile format: <name>:<PID>:<port>:<password>:<protocol>
+func readLockfile(lockfilePath string) (ConnectionInfo, error) {
+	data, err := os.ReadFile(lockfilePath)
+	if err != nil {
+		return ConnectionInfo{}, err
+	}
+	parts := strings.Split(strings.TrimSpace(string(data)), ":")
+	if len(parts) < 5 {
+		return ConnectionInfo{}, fmt.Errorf("malformed lockfile")
+	}
+	return ConnectionInfo{
+		Protocol: parts[4],
+		Address:  "127.0.0.1",
+		Port:     parts[2],
+		Username: "riot",
+		Password: parts[3],
+	}, nil
+}
+
 func GetLCUPathFromProcess() (string, error) {
 	processes, err := process.Processes()
 	if err != nil {
@@ -412,23 +1205,138 @@ func GetLCUPathFromProcess() (string, error) {
 	return "", errors.New("LCU not found")
 }
 
-func IsValidLCUPath(dir string) bool {
+// riotClientLockfilePath returns where the Riot Client writes its own lockfile
+func riotClientLockfilePath() string {
+	switch runtime.GOOS {
+	case "windows":
+		return filepath.Join(os.Getenv("LOCALAPPDATA"), "Riot Games", "Riot Client", "Config", "lockfile")
+	case "darwin":
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, "Library", "Application Support", "Riot Games", "Riot Client", "Config", "lockfile")
+	default:
+		return ""
+	}
+}
+
+// RiotClientConnection reads the Riot Client's lockfile. The Riot Client listens
+// on its own port, separate from the League client's, and serves a few endpoints
+// (region, account) that the LCU only proxies on some versions.
+func RiotClientConnection() (ConnectionInfo, error) {
+	lockfilePath := riotClientLockfilePath()
+	if lockfilePath == "" {
+		return ConnectionInfo{}, errors.New("riot client lockfile location unknown on this OS")
+	}
+	return readLockfile(lockfilePath)
+}
+
+// GetLCUPathFromRiotClient asks a running Riot Client where it launched League from.
+// It is a fallback for when the League process command line can't be read.
+func GetLCUPathFromRiotClient() (string, error) {
+	info, err := RiotClientConnection()
+	if err != nil {
+		return "", err
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		Timeout: 5 * time.Second,
+	}
+	url := fmt.Sprintf("%s://%s:%s/product-session/v1/external-sessions", info.Protocol, info.Address, info.Port)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(info.Username, info.Password)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var sessions map[string]struct {
+		ProductID           string `json:"productId"`
+		LaunchConfiguration struct {
+			Executable       string `json:"executable"`
+			WorkingDirectory string `json:"workingDirectory"`
+		} `json:"launchConfiguration"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&sessions); err != nil {
+		return "", err
+	}
+
+	for _, session := range sessions {
+		if session.ProductID != "league_of_legends" {
+			continue
+		}
+		dir := session.LaunchConfiguration.WorkingDirectory
+		if dir == "" && session.LaunchConfiguration.Executable != "" {
+			dir = filepath.Dir(session.LaunchConfiguration.Executable)
+		}
+		if dir != "" {
+			return normalizePath(dir), nil
+		}
+	}
+	return "", errors.New("no League session in Riot Client")
+}
+
+// Client distributions reported by LCUVariant
+const (
+	VariantGlobal = "Global"
+	VariantCN     = "CN"
+	VariantGarena = "Garena"
+)
+
+// LCUVariant guesses which distribution of League is installed in dir from its
+// path and its RADS/TQM markers. It returns "" when dir
+// gives no hint.
+func LCUVariant(dir string) string {
 	if dir == "" {
-		return false
+		return ""
 	}
-	var clientFile string
-	if runtime.GOOS == "darwin" {
-		clientFile = "LeagueClient.app"
-	} else {
-		clientFile = "LeagueClient.exe"
+	lower := strings.ToLower(dir)
+	switch {
+	case strings.Contains(lower, "garena"):
+		return VariantGarena
+	case strings.Contains(lower, "wegame") || strings.Contains(lower, "tencent") || strings.Contains(dir, "英雄联盟"):
+		return VariantCN
+	case dirExists(filepath.Join(dir, "TQM")):
+		return VariantCN
+	case dirExists(filepath.Join(dir, "RADS")) || strings.Contains(lower, "riot games"):
+		return VariantGlobal
 	}
+	return ""
+}
 
-	common := fileExists(filepath.Join(dir, clientFile)) && dirExists(filepath.Join(dir, "Config"))
-	isGlobal := common && dirExists(filepath.Join(dir, "RADS"))
-	isCN := common && dirExists(filepath.Join(dir, "TQM"))
-	isGarena := common
+// defaultLockfileDirs lists common League install directories across the
+// Global, CN and Garena distributions for the current OS.
+func defaultLockfileDirs() []string {
+	switch runtime.GOOS {
+	case "windows":
+		return []string{
+			`C:\Riot Games\League of Legends`,
+			`C:\Program Files\Riot Games\League of Legends`,
+			`C:\Program Files (x86)\Riot Games\League of Legends`,
+			`C:\WeGameApps\英雄联盟\LeagueClient`,
+			`C:\Garena\Games\32771`,
+		}
+	case "darwin":
+		return []string{"/Applications/League of Legends.app/Contents/LoL"}
+	default:
+		return []string{normalizePath(`C:\Riot Games\League of Legends`)}
+	}
+}
 
-	return isGlobal || isCN || isGarena
+func existingDirs(dirs []string) []string {
+	var found []string
+	for _, dir := range dirs {
+		if dirExists(dir) {
+			found = append(found, dir)
+		}
+	}
+	return found
 }
 
 func normalizePath(p string) string {
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChampSelectActionHelpers(t *testing.T) {
+	ban := func(id, cell int, inProgress, completed bool) Action {
+		return Action{ID: id, ActorCellID: cell, Type: "ban", IsInProgress: inProgress, Completed: completed}
+	}
+	pick := func(id, cell int, inProgress, completed bool) Action {
+		return Action{ID: id, ActorCellID: cell, Type: "pick", IsInProgress: inProgress, Completed: completed}
+	}
+
+	tests := []struct {
+		name        string
+		localCell   int
+		spectating  bool
+		actions     [][]Action
+		wantFlat    []Action
+		wantCurrent *Action
+		wantTurn    int
+		wantOnClock bool
+	}{
+		{
+			name:     "no action groups",
+			wantTurn: -1,
+		},
+		{
+			name:     "empty groups",
+			actions:  [][]Action{{}, {}},
+			wantTurn: -1,
+		},
+		{
+			name:      "local ban in progress alongside others",
+			localCell: 2,
+			actions: [][]Action{
+				{ban(1, 0, true, false), ban(2, 2, true, false)},
+				{pick(3, 0, false, false)},
+				{pick(4, 2, false, false)},
+			},
+			wantFlat:    []Action{ban(1, 0, true, false), ban(2, 2, true, false), pick(3, 0, false, false), pick(4, 2, false, false)},
+			wantCurrent: &Action{ID: 2, ActorCellID: 2, Type: "ban", IsInProgress: true},
+			wantTurn:    1,
+		},
+		{
+			name:      "local pick in progress in a shared turn",
+			localCell: 1,
+			actions: [][]Action{
+				{ban(1, 1, false, true)},
+				{pick(2, 0, true, false), pick(3, 1, true, false)},
+			},
+			wantFlat:    []Action{ban(1, 1, false, true), pick(2, 0, true, false), pick(3, 1, true, false)},
+			wantCurrent: &Action{ID: 3, ActorCellID: 1, Type: "pick", IsInProgress: true},
+			wantTurn:    0,
+			wantOnClock: true,
+		},
+		{
+			name:      "local actions all completed",
+			localCell: 1,
+			actions: [][]Action{
+				{ban(1, 1, false, true)},
+				{pick(2, 1, false, true)},
+				{pick(3, 0, true, false)},
+			},
+			wantFlat: []Action{ban(1, 1, false, true), pick(2, 1, false, true), pick(3, 0, true, false)},
+			wantTurn: -1,
+		},
+		{
+			name:       "spectator",
+			localCell:  -1,
+			spectating: true,
+			actions:    [][]Action{{pick(1, 0, true, false)}},
+			wantFlat:   []Action{pick(1, 0, true, false)},
+			wantTurn:   -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := ChampSelectSession{Actions: tt.actions, LocalPlayerCellID: tt.localCell, IsSpectating: tt.spectating}
+
+			if got := FlattenActions(session); !reflect.DeepEqual(got, tt.wantFlat) {
+				t.Errorf("FlattenActions = %+v, want %+v", got, tt.wantFlat)
+			}
+
+			current, ok := CurrentAction(session)
+			switch {
+			case tt.wantCurrent == nil && ok:
+				t.Errorf("CurrentAction = %+v, want none", current)
+			case tt.wantCurrent != nil && (!ok || current != *tt.wantCurrent):
+				t.Errorf("CurrentAction = %+v, %v; want %+v", current, ok, *tt.wantCurrent)
+			}
+
+			turn, onClock := LocalPickTurn(session)
+			if turn != tt.wantTurn || onClock != tt.wantOnClock {
+				t.Errorf("LocalPickTurn = %d, %v; want %d, %v", turn, onClock, tt.wantTurn, tt.wantOnClock)
+			}
+		})
+	}
+}
@@ -1,26 +1,97 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {main} from '../models';
+
+export function BanChampion(arg1:number):Promise<void>;
+
+export function ClockOffset():Promise<number>;
+
+export function DetectRegionHint():Promise<string>;
+
+export function DodgeChampSelect():Promise<void>;
+
+export function FetchLCUAsset(arg1:string):Promise<string>;
+
+export function GetChampionIcon(arg1:number):Promise<string>;
+
+export function GetChampionIconURL(arg1:number):Promise<string>;
 
 export function GetChatMe():Promise<Record<string, any>>;
 
+export function GetCompanionWindows():Promise<Array<string>>;
+
 export function GetConversations():Promise<Array<any>>;
 
+export function GetCornerMode():Promise<main.Corner>;
+
+export function GetCurrentAction():Promise<main.Action>;
+
+export function GetCurrentChampSelect():Promise<Record<string, any>>;
+
+export function GetCurrentRunePage():Promise<Record<string, any>>;
+
 export function GetCurrentSummoner():Promise<Record<string, any>>;
 
 export function GetFriends():Promise<Array<any>>;
 
+export function GetGameModeAssets(arg1:number):Promise<Record<string, any>>;
+
 export function GetLobby():Promise<Record<string, any>>;
 
 export function GetMatchHistory():Promise<Record<string, any>>;
 
+export function GetMode():Promise<boolean>;
+
+export function GetMonitorOptions():Promise<main.MonitorOptions>;
+
+export function GetOverlayOffset():Promise<main.OverlayOffset>;
+
+export function GetPickTurn():Promise<main.PickTurn>;
+
+export function GetRecentEvents(arg1:number):Promise<Array<main.EventSummary>>;
+
+export function GetRecentMatches(arg1:number):Promise<Array<main.MatchSummary>>;
+
 export function GetRegionInfo():Promise<Record<string, any>>;
 
 export function GetSummonerProfile():Promise<Record<string, any>>;
 
+export function GetSummonerSpells():Promise<Record<string, any>>;
+
+export function GetTeamIntents():Promise<Record<number, number>>;
+
+export function GetZOrderMode():Promise<string>;
+
+export function IsInChampSelect():Promise<boolean>;
+
 export function IsLCUConnected():Promise<boolean>;
 
+export function IsSpectating():Promise<boolean>;
+
+export function LCUWebSocketURL():Promise<string>;
+
+export function LastChampSelectEventAt():Promise<any>;
+
+export function LockIn(arg1:number):Promise<void>;
+
 export function PositionWindow():Promise<string>;
 
+export function ReconfigureMonitoring(arg1:main.MonitorOptions):Promise<void>;
+
+export function SendChampSelectMessage(arg1:string):Promise<void>;
+
+export function SetCompanionWindows(arg1:Array<string>):Promise<void>;
+
+export function SetCornerMode(arg1:main.Corner):Promise<void>;
+
+export function SetCurrentRunePage(arg1:Record<string, any>):Promise<void>;
+
+export function SetMode(arg1:boolean,arg2:string):Promise<void>;
+
+export function SetOverlayOffset(arg1:number,arg2:number):Promise<void>;
+
+export function SetZOrderMode(arg1:string):Promise<void>;
+
 export function StartMonitoring():Promise<string>;
 
 export function StopMonitoring():Promise<string>;
@@ -2,14 +2,62 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function BanChampion(arg1) {
+  return window['go']['main']['App']['BanChampion'](arg1);
+}
+
+export function ClockOffset() {
+  return window['go']['main']['App']['ClockOffset']();
+}
+
+export function DetectRegionHint() {
+  return window['go']['main']['App']['DetectRegionHint']();
+}
+
+export function DodgeChampSelect() {
+  return window['go']['main']['App']['DodgeChampSelect']();
+}
+
+export function FetchLCUAsset(arg1) {
+  return window['go']['main']['App']['FetchLCUAsset'](arg1);
+}
+
+export function GetChampionIcon(arg1) {
+  return window['go']['main']['App']['GetChampionIcon'](arg1);
+}
+
+export function GetChampionIconURL(arg1) {
+  return window['go']['main']['App']['GetChampionIconURL'](arg1);
+}
+
 export function GetChatMe() {
   return window['go']['main']['App']['GetChatMe']();
 }
 
+export function GetCompanionWindows() {
+  return window['go']['main']['App']['GetCompanionWindows']();
+}
+
 export function GetConversations() {
   return window['go']['main']['App']['GetConversations']();
 }
 
+export function GetCornerMode() {
+  return window['go']['main']['App']['GetCornerMode']();
+}
+
+export function GetCurrentAction() {
+  return window['go']['main']['App']['GetCurrentAction']();
+}
+
+export function GetCurrentChampSelect() {
+  return window['go']['main']['App']['GetCurrentChampSelect']();
+}
+
+export function GetCurrentRunePage() {
+  return window['go']['main']['App']['GetCurrentRunePage']();
+}
+
 export function GetCurrentSummoner() {
   return window['go']['main']['App']['GetCurrentSummoner']();
 }
@@ -18,6 +66,10 @@ export function GetFriends() {
   return window['go']['main']['App']['GetFriends']();
 }
 
+export function GetGameModeAssets(arg1) {
+  return window['go']['main']['App']['GetGameModeAssets'](arg1);
+}
+
 export function GetLobby() {
   return window['go']['main']['App']['GetLobby']();
 }
@@ -26,6 +78,30 @@ export function GetMatchHistory() {
   return window['go']['main']['App']['GetMatchHistory']();
 }
 
+export function GetMode() {
+  return window['go']['main']['App']['GetMode']();
+}
+
+export function GetMonitorOptions() {
+  return window['go']['main']['App']['GetMonitorOptions']();
+}
+
+export function GetOverlayOffset() {
+  return window['go']['main']['App']['GetOverlayOffset']();
+}
+
+export function GetPickTurn() {
+  return window['go']['main']['App']['GetPickTurn']();
+}
+
+export function GetRecentEvents(arg1) {
+  return window['go']['main']['App']['GetRecentEvents'](arg1);
+}
+
+export function GetRecentMatches(arg1) {
+  return window['go']['main']['App']['GetRecentMatches'](arg1);
+}
+
 export function GetRegionInfo() {
   return window['go']['main']['App']['GetRegionInfo']();
 }
@@ -34,14 +110,78 @@ export function GetSummonerProfile() {
   return window['go']['main']['App']['GetSummonerProfile']();
 }
 
+export function GetSummonerSpells() {
+  return window['go']['main']['App']['GetSummonerSpells']();
+}
+
+export function GetTeamIntents() {
+  return window['go']['main']['App']['GetTeamIntents']();
+}
+
+export function GetZOrderMode() {
+  return window['go']['main']['App']['GetZOrderMode']();
+}
+
+export function IsInChampSelect() {
+  return window['go']['main']['App']['IsInChampSelect']();
+}
+
 export function IsLCUConnected() {
   return window['go']['main']['App']['IsLCUConnected']();
 }
 
+export function IsSpectating() {
+  return window['go']['main']['App']['IsSpectating']();
+}
+
+export function LCUWebSocketURL() {
+  return window['go']['main']['App']['LCUWebSocketURL']();
+}
+
+export function LastChampSelectEventAt() {
+  return window['go']['main']['App']['LastChampSelectEventAt']();
+}
+
+export function LockIn(arg1) {
+  return window['go']['main']['App']['LockIn'](arg1);
+}
+
 export function PositionWindow() {
   return window['go']['main']['App']['PositionWindow']();
 }
 
+export function ReconfigureMonitoring(arg1) {
+  return window['go']['main']['App']['ReconfigureMonitoring'](arg1);
+}
+
+export function SendChampSelectMessage(arg1) {
+  return window['go']['main']['App']['SendChampSelectMessage'](arg1);
+}
+
+export function SetCompanionWindows(arg1) {
+  return window['go']['main']['App']['SetCompanionWindows'](arg1);
+}
+
+export function SetCornerMode(arg1) {
+  return window['go']['main']['App']['SetCornerMode'](arg1);
+}
+
+export function SetCurrentRunePage(arg1) {
+  return window['go']['main']['App']['SetCurrentRunePage'](arg1);
+}
+
+export function SetMode(arg1, arg2) {
+  return window['go']['main']['App']['SetMode'](arg1, arg2);
+}
+
+export function SetOverlayOffset(arg1, arg2) {
+  return window['go']['main']['App']['SetOverlayOffset'](arg1, arg2);
+}
+
+export function SetZOrderMode(arg1) {
+  return window['go']['main']['App']['SetZOrderMode'](arg1);
+}
+
 export function StartMonitoring() {
   return window['go']['main']['App']['StartMonitoring']();
 }
@@ -0,0 +1,128 @@
+export namespace main {
+	
+	export class Action {
+	    actorCellId: number;
+	    championId: number;
+	    completed: boolean;
+	    isAllyAction: boolean;
+	    isInProgress: boolean;
+	    type: string;
+	    pickTurn: number;
+	    duration: number;
+	    id: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Action(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.actorCellId = source["actorCellId"];
+	        this.championId = source["championId"];
+	        this.completed = source["completed"];
+	        this.isAllyAction = source["isAllyAction"];
+	        this.isInProgress = source["isInProgress"];
+	        this.type = source["type"];
+	        this.pickTurn = source["pickTurn"];
+	        this.duration = source["duration"];
+	        this.id = source["id"];
+	    }
+	}
+	export class EventSummary {
+	    timestamp: string;
+	    type: string;
+	    phase: string;
+	    summary: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EventSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.timestamp = source["timestamp"];
+	        this.type = source["type"];
+	        this.phase = source["phase"];
+	        this.summary = source["summary"];
+	    }
+	}
+	export class MatchSummary {
+	    gameId: number;
+	    queueId: number;
+	    gameMode: string;
+	    championId: number;
+	    win: boolean;
+	    kills: number;
+	    deaths: number;
+	    assists: number;
+	    kda: number;
+	    playedAt: string;
+	    duration: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MatchSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.gameId = source["gameId"];
+	        this.queueId = source["queueId"];
+	        this.gameMode = source["gameMode"];
+	        this.championId = source["championId"];
+	        this.win = source["win"];
+	        this.kills = source["kills"];
+	        this.deaths = source["deaths"];
+	        this.assists = source["assists"];
+	        this.kda = source["kda"];
+	        this.playedAt = source["playedAt"];
+	        this.duration = source["duration"];
+	    }
+	}
+	export class MonitorOptions {
+	    dockSide: string;
+	    width: number;
+	    gap: number;
+	    fullscreen: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MonitorOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dockSide = source["dockSide"];
+	        this.width = source["width"];
+	        this.gap = source["gap"];
+	        this.fullscreen = source["fullscreen"];
+	    }
+	}
+	export class OverlayOffset {
+	    x: number;
+	    y: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new OverlayOffset(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.x = source["x"];
+	        this.y = source["y"];
+	    }
+	}
+	export class PickTurn {
+	    turn: number;
+	    onClock: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PickTurn(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.turn = source["turn"];
+	        this.onClock = source["onClock"];
+	    }
+	}
+
+}
@@ -0,0 +1,99 @@
+// Package champselect performs the local player's champ-select actions through
+// the LCU's HTTP API. The overlay uses it for LockIn and BanChampion, and the
+// mock tool's live mode uses it to replay a capture's picks and bans into a
+// real client.
+package champselect
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SessionEndpoint is the LCU's champ-select session resource
+const SessionEndpoint = "/lol-champ-select/v1/session"
+
+// ErrNoTurn is returned when the local player has no action of the requested
+// type in progress
+var ErrNoTurn = errors.New("no action of that type is in progress for the local player")
+
+// Requester sends a request with an optional JSON body to the LCU and returns
+// the decoded JSON object
+type Requester func(method, endpoint string, payload interface{}) (map[string]interface{}, error)
+
+// LocalAction returns the id of the local player's in-progress action of
+// actionType ("pick" or "ban") in a decoded session
+func LocalAction(session map[string]interface{}, actionType string) (int, bool) {
+	localCell, ok := number(session["localPlayerCellId"])
+	if !ok {
+		return 0, false
+	}
+	groups, _ := session["actions"].([]interface{})
+	for _, group := range groups {
+		actions, _ := group.([]interface{})
+		for _, entry := range actions {
+			action, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			cell, _ := number(action["actorCellId"])
+			kind, _ := action["type"].(string)
+			inProgress, _ := action["isInProgress"].(bool)
+			completed, _ := action["completed"].(bool)
+			if cell != localCell || kind != actionType || !inProgress || completed {
+				continue
+			}
+			if id, ok := number(action["id"]); ok {
+				return int(id), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// Hover shows championID on the action without locking it in
+func Hover(do Requester, actionID, championID int) error {
+	endpoint := fmt.Sprintf("%s/actions/%d", SessionEndpoint, actionID)
+	if _, err := do("PATCH", endpoint, map[string]interface{}{"championId": championID}); err != nil {
+		return fmt.Errorf("failed to hover champion %d: %w", championID, err)
+	}
+	return nil
+}
+
+// Complete locks in whatever the action currently has hovered
+func Complete(do Requester, actionID int) error {
+	endpoint := fmt.Sprintf("%s/actions/%d/complete", SessionEndpoint, actionID)
+	if _, err := do("POST", endpoint, nil); err != nil {
+		return fmt.Errorf("failed to complete action %d: %w", actionID, err)
+	}
+	return nil
+}
+
+// Act hovers championID on the local player's in-progress action of
+// actionType and locks it in. It returns ErrNoTurn when it isn't their turn.
+func Act(do Requester, actionType string, championID int) error {
+	session, err := do("GET", SessionEndpoint, nil)
+	if err != nil {
+		return err
+	}
+	actionID, ok := LocalAction(session, actionType)
+	if !ok {
+		return fmt.Errorf("%s: %w", actionType, ErrNoTurn)
+	}
+	if err := Hover(do, actionID, championID); err != nil {
+		return err
+	}
+	return Complete(do, actionID)
+}
+
+// number reads a JSON number that may have been decoded as float64 or built as an int
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,135 @@
+package champselect
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func action(id, cell int, kind string, inProgress, completed bool) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           float64(id),
+		"actorCellId":  float64(cell),
+		"type":         kind,
+		"isInProgress": inProgress,
+		"completed":    completed,
+	}
+}
+
+func sessionWith(localCell int, groups ...[]interface{}) map[string]interface{} {
+	actions := make([]interface{}, len(groups))
+	for i, group := range groups {
+		actions[i] = group
+	}
+	return map[string]interface{}{"localPlayerCellId": float64(localCell), "actions": actions}
+}
+
+func TestLocalAction(t *testing.T) {
+	tests := []struct {
+		name       string
+		session    map[string]interface{}
+		actionType string
+		wantID     int
+		wantOK     bool
+	}{
+		{
+			name:       "no actions",
+			session:    sessionWith(2),
+			actionType: "pick",
+		},
+		{
+			name:       "missing local cell",
+			session:    map[string]interface{}{"actions": []interface{}{[]interface{}{action(1, 2, "pick", true, false)}}},
+			actionType: "pick",
+		},
+		{
+			name:       "local pick in progress",
+			session:    sessionWith(2, []interface{}{action(1, 0, "ban", false, true)}, []interface{}{action(7, 2, "pick", true, false)}),
+			actionType: "pick",
+			wantID:     7,
+			wantOK:     true,
+		},
+		{
+			name:       "simultaneous bans pick the local one",
+			session:    sessionWith(2, []interface{}{action(1, 0, "ban", true, false), action(3, 2, "ban", true, false)}),
+			actionType: "ban",
+			wantID:     3,
+			wantOK:     true,
+		},
+		{
+			name:       "wrong type",
+			session:    sessionWith(2, []interface{}{action(3, 2, "ban", true, false)}),
+			actionType: "pick",
+		},
+		{
+			name:       "someone else's turn",
+			session:    sessionWith(2, []interface{}{action(4, 1, "pick", true, false)}),
+			actionType: "pick",
+		},
+		{
+			name:       "not started yet",
+			session:    sessionWith(2, []interface{}{action(5, 2, "pick", false, false)}),
+			actionType: "pick",
+		},
+		{
+			name:       "already completed",
+			session:    sessionWith(2, []interface{}{action(6, 2, "pick", true, true)}),
+			actionType: "pick",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := LocalAction(tt.session, tt.actionType)
+			if id != tt.wantID || ok != tt.wantOK {
+				t.Errorf("LocalAction = %d, %v; want %d, %v", id, ok, tt.wantID, tt.wantOK)
+			}
+		})
+	}
+}
+
+type call struct {
+	Method   string
+	Endpoint string
+	Payload  interface{}
+}
+
+// recorder answers session requests with session and logs every call
+func recorder(session map[string]interface{}, calls *[]call) Requester {
+	return func(method, endpoint string, payload interface{}) (map[string]interface{}, error) {
+		*calls = append(*calls, call{method, endpoint, payload})
+		if method == "GET" && endpoint == SessionEndpoint {
+			return session, nil
+		}
+		return map[string]interface{}{}, nil
+	}
+}
+
+func TestAct(t *testing.T) {
+	var calls []call
+	session := sessionWith(2, []interface{}{action(9, 2, "ban", true, false)})
+	if err := Act(recorder(session, &calls), "ban", 157); err != nil {
+		t.Fatalf("Act: %v", err)
+	}
+
+	want := []call{
+		{"GET", "/lol-champ-select/v1/session", nil},
+		{"PATCH", "/lol-champ-select/v1/session/actions/9", map[string]interface{}{"championId": 157}},
+		{"POST", "/lol-champ-select/v1/session/actions/9/complete", nil},
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %+v, want %+v", calls, want)
+	}
+}
+
+func TestActNoTurn(t *testing.T) {
+	var calls []call
+	session := sessionWith(2, []interface{}{action(9, 2, "ban", true, false)})
+	err := Act(recorder(session, &calls), "pick", 157)
+	if !errors.Is(err, ErrNoTurn) {
+		t.Fatalf("Act = %v, want ErrNoTurn", err)
+	}
+	if len(calls) != 1 {
+		t.Errorf("made %d calls, want only the session lookup", len(calls))
+	}
+}
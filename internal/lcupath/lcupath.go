@@ -0,0 +1,41 @@
+// Package lcupath recognizes League client install directories. It is shared
+// by the overlay and the capture tool so both accept the same installs.
+package lcupath
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// IsValid reports whether dir holds a League client install: the client
+// binary next to a Config directory. Global installs also carry RADS and CN
+// installs TQM, but Garena ships neither, so those markers aren't required.
+func IsValid(dir string) bool {
+	return isValid(runtime.GOOS, dir)
+}
+
+// isValid is IsValid for the client layout of goos
+func isValid(goos, dir string) bool {
+	if dir == "" {
+		return false
+	}
+	var hasClient bool
+	if goos == "darwin" {
+		// The macOS client is an app bundle, which is a directory
+		hasClient = dirExists(filepath.Join(dir, "LeagueClient.app"))
+	} else {
+		hasClient = fileExists(filepath.Join(dir, "LeagueClient.exe"))
+	}
+	return hasClient && dirExists(filepath.Join(dir, "Config"))
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
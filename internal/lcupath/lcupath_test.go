@@ -0,0 +1,52 @@
+package lcupath
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsValid(t *testing.T) {
+	// Each layout lists directories (trailing slash) and empty files to create
+	tests := []struct {
+		name   string
+		goos   string
+		layout []string
+		want   bool
+	}{
+		{"mac bundle directory", "darwin", []string{"LeagueClient.app/Contents/", "Config/"}, true},
+		{"mac bundle as a plain file", "darwin", []string{"LeagueClient.app", "Config/"}, false},
+		{"mac missing Config", "darwin", []string{"LeagueClient.app/"}, false},
+		{"mac with only the windows binary", "darwin", []string{"LeagueClient.exe", "Config/"}, false},
+		{"windows global", "windows", []string{"LeagueClient.exe", "Config/", "RADS/"}, true},
+		{"windows garena", "windows", []string{"LeagueClient.exe", "Config/"}, true},
+		{"windows binary as a directory", "windows", []string{"LeagueClient.exe/", "Config/"}, false},
+		{"windows missing Config", "windows", []string{"LeagueClient.exe"}, false},
+		{"windows with only the mac bundle", "windows", []string{"LeagueClient.app/", "Config/"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, entry := range tt.layout {
+				path := filepath.Join(dir, filepath.FromSlash(entry))
+				var err error
+				if entry[len(entry)-1] == '/' {
+					err = os.MkdirAll(path, 0o755)
+				} else {
+					err = os.WriteFile(path, nil, 0o644)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := isValid(tt.goos, dir); got != tt.want {
+				t.Errorf("isValid(%q, %v) = %v, want %v", tt.goos, tt.layout, got, tt.want)
+			}
+		})
+	}
+
+	if isValid("windows", "") || isValid("darwin", "") {
+		t.Error("an empty dir must not be valid")
+	}
+}
@@ -0,0 +1,137 @@
+package mockreplay
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ActionEvent is a pick or ban that completed at a given step.
+type ActionEvent struct {
+	StepIndex    int
+	Timestamp    time.Time
+	ActionID     int
+	Type         string // "pick" or "ban"
+	ActorCellID  int
+	ChampionID   int
+	IsAllyAction bool
+	Summary      string
+}
+
+// sessionAction is the subset of a champ-select action needed for the draft log.
+type sessionAction struct {
+	ID           int    `json:"id"`
+	ActorCellID  int    `json:"actorCellId"`
+	ChampionID   int    `json:"championId"`
+	Completed    bool   `json:"completed"`
+	IsAllyAction bool   `json:"isAllyAction"`
+	Type         string `json:"type"`
+}
+
+// ActionEvents derives a chronological draft log from raw session updates,
+// emitting each pick or ban once, at the step where it first shows as completed.
+func ActionEvents(steps []Step) []ActionEvent {
+	var events []ActionEvent
+	seen := make(map[int]bool)
+
+	for _, step := range steps {
+		for _, action := range stepActions(step.Raw) {
+			if !action.Completed || seen[action.ID] {
+				continue
+			}
+			if action.Type != "pick" && action.Type != "ban" {
+				continue
+			}
+			seen[action.ID] = true
+
+			events = append(events, ActionEvent{
+				StepIndex:    step.Index,
+				Timestamp:    step.Timestamp,
+				ActionID:     action.ID,
+				Type:         action.Type,
+				ActorCellID:  action.ActorCellID,
+				ChampionID:   action.ChampionID,
+				IsAllyAction: action.IsAllyAction,
+				Summary:      actionSummary(action),
+			})
+		}
+	}
+
+	return events
+}
+
+// LocalActions returns the picks and bans the capturing player made, in draft
+// order. A capture from a spectator has none.
+func LocalActions(steps []Step) []ActionEvent {
+	localCell, ok := localPlayerCell(steps)
+	if !ok {
+		return nil
+	}
+	var local []ActionEvent
+	for _, event := range ActionEvents(steps) {
+		if event.IsAllyAction && event.ActorCellID == localCell {
+			local = append(local, event)
+		}
+	}
+	return local
+}
+
+// localPlayerCell reads localPlayerCellId from the first session update that
+// carries one. Spectators have no cell and report -1.
+func localPlayerCell(steps []Step) (int, bool) {
+	for _, step := range steps {
+		var arr []json.RawMessage
+		if err := json.Unmarshal(step.Raw, &arr); err != nil || len(arr) < 3 {
+			continue
+		}
+		var event struct {
+			Data struct {
+				LocalPlayerCellID *int `json:"localPlayerCellId"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(arr[2], &event); err != nil || event.Data.LocalPlayerCellID == nil {
+			continue
+		}
+		cell := *event.Data.LocalPlayerCellID
+		return cell, cell >= 0
+	}
+	return 0, false
+}
+
+// stepActions flattens the action groups of a [type, name, event] payload.
+func stepActions(raw json.RawMessage) []sessionAction {
+	var arr []json.RawMessage
+	if err := json.Unmarshal(raw, &arr); err != nil || len(arr) < 3 {
+		return nil
+	}
+
+	var event struct {
+		Data struct {
+			Actions [][]sessionAction `json:"actions"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(arr[2], &event); err != nil {
+		return nil
+	}
+
+	var actions []sessionAction
+	for _, group := range event.Data.Actions {
+		actions = append(actions, group...)
+	}
+	return actions
+}
+
+func actionSummary(action sessionAction) string {
+	side := "enemy"
+	if action.IsAllyAction {
+		side = "ally"
+	}
+	verb := "picked"
+	if action.Type == "ban" {
+		verb = "banned"
+	}
+	if action.ChampionID == 0 {
+		return fmt.Sprintf("%s cell %d %s nothing", side, action.ActorCellID, verb)
+	}
+	return fmt.Sprintf("%s cell %d %s champion %d", side, action.ActorCellID, verb, action.ChampionID)
+}
@@ -0,0 +1,118 @@
+package mockreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// anonymizer hands out stable fake values so the same player maps to the same
+// alias everywhere in a capture.
+type anonymizer struct {
+	names   map[string]string
+	tags    map[string]string
+	puuids  map[string]string
+	summIDs map[string]json.Number
+}
+
+func newAnonymizer() *anonymizer {
+	return &anonymizer{
+		names:   make(map[string]string),
+		tags:    make(map[string]string),
+		puuids:  make(map[string]string),
+		summIDs: make(map[string]json.Number),
+	}
+}
+
+// Anonymize returns a copy of session with player-identifying fields (gameName,
+// tagLine, puuid, summonerId) replaced by deterministic fake values. Empty or
+// zero values, which the client uses for hidden names, are left alone.
+func Anonymize(session *CaptureSession) (*CaptureSession, error) {
+	an := newAnonymizer()
+	out := *session
+	out.Events = make([]CapturedEvent, len(session.Events))
+
+	for i, ev := range session.Events {
+		dec := json.NewDecoder(bytes.NewReader(ev.RawData))
+		dec.UseNumber()
+
+		var raw any
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("event %d: %w", i, err)
+		}
+
+		data, err := json.Marshal(an.walk(raw))
+		if err != nil {
+			return nil, fmt.Errorf("event %d: %w", i, err)
+		}
+
+		ev.RawData = data
+		out.Events[i] = ev
+	}
+
+	return &out, nil
+}
+
+func (an *anonymizer) walk(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for key, val := range t {
+			t[key] = an.replace(key, an.walk(val))
+		}
+		return t
+	case []any:
+		for i, val := range t {
+			t[i] = an.walk(val)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+func (an *anonymizer) replace(key string, v any) any {
+	switch key {
+	case "gameName", "summonerName", "displayName":
+		if s, ok := v.(string); ok && s != "" {
+			return alias(an.names, s, "Player%d")
+		}
+	case "tagLine":
+		if s, ok := v.(string); ok && s != "" {
+			return alias(an.tags, s, "TAG%d")
+		}
+	case "puuid":
+		if s, ok := v.(string); ok && s != "" {
+			return alias(an.puuids, s, "00000000-0000-0000-0000-%012d")
+		}
+	case "summonerId":
+		var id string
+		switch n := v.(type) {
+		case json.Number:
+			id = n.String()
+		case string:
+			id = n
+		}
+		if id == "" || id == "0" {
+			return v
+		}
+		fake, ok := an.summIDs[id]
+		if !ok {
+			fake = json.Number(fmt.Sprintf("%d", len(an.summIDs)+1))
+			an.summIDs[id] = fake
+		}
+		if _, isString := v.(string); isString {
+			return fake.String()
+		}
+		return fake
+	}
+	return v
+}
+
+func alias(seen map[string]string, original, format string) string {
+	if fake, ok := seen[original]; ok {
+		return fake
+	}
+	fake := fmt.Sprintf(format, len(seen)+1)
+	seen[original] = fake
+	return fake
+}
@@ -0,0 +1,72 @@
+package mockreplay
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// benchState is the part of an ARAM-style session needed to tell bench swaps
+// from rerolls: the champions on the shared bench and each ally's champion.
+type benchState struct {
+	bench map[int]bool
+	picks map[int]int // cellId -> championId
+}
+
+// parseBenchState reads the bench from a [type, name, event] payload. It returns
+// false for payloads without a bench, so non-ARAM queues are left alone.
+func parseBenchState(raw json.RawMessage) (benchState, bool) {
+	var arr []json.RawMessage
+	if err := json.Unmarshal(raw, &arr); err != nil || len(arr) < 3 {
+		return benchState{}, false
+	}
+
+	var event struct {
+		Data struct {
+			BenchEnabled   bool `json:"benchEnabled"`
+			BenchChampions []struct {
+				ChampionID int `json:"championId"`
+			} `json:"benchChampions"`
+			MyTeam []struct {
+				CellID     int `json:"cellId"`
+				ChampionID int `json:"championId"`
+			} `json:"myTeam"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(arr[2], &event); err != nil || !event.Data.BenchEnabled {
+		return benchState{}, false
+	}
+
+	state := benchState{bench: make(map[int]bool), picks: make(map[int]int)}
+	for _, champ := range event.Data.BenchChampions {
+		state.bench[champ.ChampionID] = true
+	}
+	for _, member := range event.Data.MyTeam {
+		state.picks[member.CellID] = member.ChampionID
+	}
+	return state, true
+}
+
+// benchChanges describes how each ally's champion changed between two states.
+// Taking a champion that was on the bench is a "BENCH swap"; getting a new one
+// while the old one lands on the bench is a "REROLL". Other changes are plain
+// picks and already show up in the draft log, so they are not reported.
+func benchChanges(prev, cur benchState) string {
+	var changes []string
+	for cell, champ := range cur.picks {
+		old, ok := prev.picks[cell]
+		if !ok || old == champ || champ == 0 {
+			continue
+		}
+		switch {
+		case prev.bench[champ]:
+			changes = append(changes, fmt.Sprintf("BENCH swap cell %d: %d -> %d", cell, old, champ))
+		case old != 0 && cur.bench[old]:
+			changes = append(changes, fmt.Sprintf("REROLL cell %d: %d -> %d", cell, old, champ))
+		}
+	}
+	// Map order is random; keep REPL output stable
+	sort.Strings(changes)
+	return strings.Join(changes, ", ")
+}
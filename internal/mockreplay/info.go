@@ -0,0 +1,94 @@
+package mockreplay
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// CaptureInfo is a quick triage summary of a capture file.
+type CaptureInfo struct {
+	Path           string
+	FileSize       int64
+	Start          time.Time
+	End            time.Time
+	Duration       time.Duration
+	EventCount     int
+	Phases         []string
+	EndsWithDelete bool
+}
+
+// Info loads a capture and summarizes it.
+func Info(path string) (*CaptureInfo, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat capture: %w", err)
+	}
+
+	session, err := LoadCapture(path)
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := BuildSteps(session)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &CaptureInfo{
+		Path:       path,
+		FileSize:   stat.Size(),
+		Start:      parseTime(session.StartTime),
+		End:        parseTime(session.EndTime),
+		EventCount: len(steps),
+	}
+
+	// Event timestamps are more precise than the session's second-resolution bounds.
+	if len(steps) > 0 {
+		if first := steps[0].Timestamp; !first.IsZero() {
+			info.Start = first
+		}
+		if last := steps[len(steps)-1].Timestamp; !last.IsZero() {
+			info.End = last
+		}
+		info.EndsWithDelete = strings.EqualFold(steps[len(steps)-1].EventType, "Delete")
+	}
+	if !info.Start.IsZero() && !info.End.IsZero() {
+		info.Duration = info.End.Sub(info.Start)
+	}
+
+	seen := make(map[string]struct{})
+	for _, step := range steps {
+		if step.Phase == "" {
+			continue
+		}
+		if _, ok := seen[step.Phase]; ok {
+			continue
+		}
+		seen[step.Phase] = struct{}{}
+		info.Phases = append(info.Phases, step.Phase)
+	}
+
+	return info, nil
+}
+
+// String renders the summary in the multi-line form printed by `capture info`.
+func (i *CaptureInfo) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "File:        %s (%d bytes)\n", i.Path, i.FileSize)
+	fmt.Fprintf(&b, "Start:       %s\n", formatInfoTime(i.Start))
+	fmt.Fprintf(&b, "End:         %s\n", formatInfoTime(i.End))
+	fmt.Fprintf(&b, "Duration:    %s\n", i.Duration)
+	fmt.Fprintf(&b, "Events:      %d\n", i.EventCount)
+	fmt.Fprintf(&b, "Phases:      %d %v\n", len(i.Phases), i.Phases)
+	fmt.Fprintf(&b, "Ends Delete: %t\n", i.EndsWithDelete)
+	return b.String()
+}
+
+func formatInfoTime(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return t.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,29 @@
+package mockreplay
+
+import "fmt"
+
+// queueNames maps common LCU queue ids to readable names.
+var queueNames = map[int]string{
+	400:  "Normal Draft",
+	420:  "Ranked Solo/Duo",
+	430:  "Normal Blind",
+	440:  "Ranked Flex",
+	450:  "ARAM",
+	490:  "Quickplay",
+	700:  "Clash",
+	720:  "ARAM Clash",
+	830:  "Co-op vs AI Intro",
+	840:  "Co-op vs AI Beginner",
+	850:  "Co-op vs AI Intermediate",
+	900:  "ARURF",
+	1700: "Arena",
+	1900: "URF",
+}
+
+// QueueName returns a readable name for a queue id, falling back to the id itself.
+func QueueName(queueID int) string {
+	if name, ok := queueNames[queueID]; ok {
+		return name
+	}
+	return fmt.Sprintf("queue %d", queueID)
+}
@@ -1,24 +1,34 @@
 package mockreplay
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"time"
 )
 
 // CapturedEvent mirrors the capture format used in capture/main.go.
 type CapturedEvent struct {
-	Timestamp string          `json:"timestamp"`
-	RawData   json.RawMessage `json:"rawData"`
+	Timestamp       string          `json:"timestamp"`
+	OffsetMs        *int64          `json:"offsetMs,omitempty"` // monotonic offset from capture start, if recorded
+	GameID          int64           `json:"gameId,omitempty"`
+	ClientLatencyMs *int64          `json:"clientLatencyMs,omitempty"` // receive time minus the client's timer.internalNowInEpochMs
+	Kind            string          `json:"kind,omitempty"`            // "chat" for champ-select chat messages
+	GapCompressed   bool            `json:"gapCompressed,omitempty"`   // follows a long pause in the original champ select
+	RawData         json.RawMessage `json:"rawData"`
 }
 
 // CaptureSession is the full capture payload.
 type CaptureSession struct {
-	StartTime  string          `json:"startTime"`
-	EndTime    string          `json:"endTime,omitempty"`
-	EventCount int             `json:"eventCount"`
-	Events     []CapturedEvent `json:"events"`
+	StartTime  string            `json:"startTime"`
+	EndTime    string            `json:"endTime,omitempty"`
+	EventCount int               `json:"eventCount"`
+	Metadata   map[string]string `json:"metadata,omitempty"` // free-form tags such as patch, queue or notes
+	Events     []CapturedEvent   `json:"events"`
 }
 
 // Step is a replay-ready unit derived from a captured event.
@@ -28,9 +38,18 @@ type Step struct {
 	Raw       json.RawMessage
 	EventType string
 	Summary   string
+	Phase     string
+	GameID    int64
+	Kind      string // "chat" for chat messages, empty for session events
+	AfterGap  bool   // the capture flagged a long pause before this step
 }
 
-// LoadCapture parses a capture file into a CaptureSession.
+// ErrNoEvents is returned by CheckEvents for a well-formed capture that never
+// recorded anything, typically because it was stopped before champ select began.
+var ErrNoEvents = errors.New("capture has no events; it never recorded a champ select")
+
+// LoadCapture parses a capture file into a CaptureSession. A capture with no
+// events loads fine; use CheckEvents before replaying it.
 func LoadCapture(path string) (*CaptureSession, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -41,28 +60,284 @@ func LoadCapture(path string) (*CaptureSession, error) {
 	if err := json.Unmarshal(data, &session); err != nil {
 		return nil, fmt.Errorf("parse capture: %w", err)
 	}
+	if session.StartTime == "" && session.Events == nil {
+		return nil, fmt.Errorf("parse capture: %s is not a capture file (no startTime or events)", path)
+	}
 
 	return &session, nil
 }
 
+// CheckEvents returns ErrNoEvents when the session has nothing to replay.
+func (s *CaptureSession) CheckEvents() error {
+	if len(s.Events) == 0 {
+		return ErrNoEvents
+	}
+	return nil
+}
+
+// LoadCaptureDir loads every .json capture in dir and concatenates them, ordered
+// by start time, into a single session. Events from overlapping captures are
+// interleaved by timestamp and exact duplicates are dropped.
+func LoadCaptureDir(dir string) (*CaptureSession, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list captures: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no captures found in %s", dir)
+	}
+
+	sessions := make([]*CaptureSession, 0, len(paths))
+	for _, path := range paths {
+		session, err := LoadCapture(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	return Merge(sessions...), nil
+}
+
+// Merge interleaves the events of several captures by timestamp into one
+// session, e.g. champ-select and chat captures recorded separately. Events with
+// identical timestamps keep the order of their captures' start times, exact
+// duplicates are dropped, and the inputs are left unchanged. If any timestamp
+// fails to parse, events are concatenated in capture order instead. Metadata
+// is combined, with later captures winning on conflicting keys.
+func Merge(sessions ...*CaptureSession) *CaptureSession {
+	ordered := make([]*CaptureSession, 0, len(sessions))
+	for _, session := range sessions {
+		if session == nil {
+			continue
+		}
+		// resolveOffsets rewrites events in place, so work on a copy
+		copied := *session
+		copied.Events = append([]CapturedEvent(nil), session.Events...)
+		ordered = append(ordered, &copied)
+	}
+	if len(ordered) == 0 {
+		return &CaptureSession{}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return parseTime(ordered[i].StartTime).Before(parseTime(ordered[j].StartTime))
+	})
+
+	combined := &CaptureSession{StartTime: ordered[0].StartTime}
+	var endTime time.Time
+	for _, session := range ordered {
+		resolveOffsets(session)
+		combined.Events = append(combined.Events, session.Events...)
+		for key, value := range session.Metadata {
+			if combined.Metadata == nil {
+				combined.Metadata = make(map[string]string)
+			}
+			combined.Metadata[key] = value
+		}
+		if end := parseTime(session.EndTime); end.After(endTime) {
+			endTime = end
+			combined.EndTime = session.EndTime
+		}
+	}
+
+	// Only reorder when every timestamp parses; otherwise keep capture order.
+	sortable := true
+	for _, ev := range combined.Events {
+		if parseTime(ev.Timestamp).IsZero() {
+			sortable = false
+			break
+		}
+	}
+	if sortable {
+		sort.SliceStable(combined.Events, func(i, j int) bool {
+			return parseTime(combined.Events[i].Timestamp).Before(parseTime(combined.Events[j].Timestamp))
+		})
+	}
+
+	deduped := combined.Events[:0]
+	for i, ev := range combined.Events {
+		if i > 0 {
+			prev := deduped[len(deduped)-1]
+			if prev.Timestamp == ev.Timestamp && bytes.Equal(prev.RawData, ev.RawData) {
+				continue
+			}
+		}
+		deduped = append(deduped, ev)
+	}
+	combined.Events = deduped
+	combined.EventCount = len(combined.Events)
+
+	return combined
+}
+
 // BuildSteps converts capture events to replay steps.
 func BuildSteps(session *CaptureSession) ([]Step, error) {
 	steps := make([]Step, 0, len(session.Events))
+	// Anchor up front, so events ahead of the first anchoring one use it too
+	var builder StepBuilder
+	builder.anchor, builder.hasAnchor = offsetAnchor(session.Events)
+	for _, ev := range session.Events {
+		steps = append(steps, builder.Add(ev))
+	}
+	return steps, nil
+}
+
+// StepBuilder converts capture events to replay steps one at a time, so a
+// capture that is still being written can be extended without rebuilding the
+// steps already made. The zero value starts a new capture. A copy carries on
+// independently of the original.
+type StepBuilder struct {
+	next         int
+	anchor       time.Time
+	hasAnchor    bool
+	prevBench    benchState
+	hasPrevBench bool
+}
+
+// Add converts the next event of the capture.
+func (b *StepBuilder) Add(ev CapturedEvent) Step {
+	ts := parseTime(ev.Timestamp)
+	if !b.hasAnchor {
+		b.anchor, b.hasAnchor = offsetAnchor([]CapturedEvent{ev})
+	}
+	if b.hasAnchor && ev.OffsetMs != nil {
+		// Monotonic offsets keep ordering stable across wall-clock adjustments.
+		ts = b.anchor.Add(time.Duration(*ev.OffsetMs) * time.Millisecond)
+	}
+	eventType, summary, phase := Summarize(ev.RawData)
+	if bench, ok := parseBenchState(ev.RawData); ok {
+		if b.hasPrevBench {
+			if changes := benchChanges(b.prevBench, bench); changes != "" {
+				summary += " | " + changes
+			}
+		}
+		b.prevBench, b.hasPrevBench = bench, true
+	}
+	if ev.GapCompressed {
+		summary += " | after pause"
+	}
+	gameID := ev.GameID
+	if gameID == 0 {
+		gameID = gameIDFromRaw(ev.RawData)
+	}
 
-	for idx, ev := range session.Events {
+	step := Step{
+		Index:     b.next,
+		Timestamp: ts,
+		Raw:       ev.RawData,
+		EventType: eventType,
+		Summary:   summary,
+		Phase:     phase,
+		GameID:    gameID,
+		Kind:      ev.Kind,
+		AfterGap:  ev.GapCompressed,
+	}
+	b.next++
+	return step
+}
+
+// GameIDs returns the distinct game ids present in steps, in order of first appearance.
+func GameIDs(steps []Step) []int64 {
+	seen := make(map[int64]struct{})
+	var ids []int64
+	for _, step := range steps {
+		if step.GameID == 0 {
+			continue
+		}
+		if _, ok := seen[step.GameID]; ok {
+			continue
+		}
+		seen[step.GameID] = struct{}{}
+		ids = append(ids, step.GameID)
+	}
+	return ids
+}
+
+// FilterByGame keeps only the steps belonging to gameID and re-indexes them.
+// Untagged steps (such as a Delete, which carries no session) are kept when they
+// directly follow a step from the same game.
+func FilterByGame(steps []Step, gameID int64) []Step {
+	var filtered []Step
+	inGame := false
+	for _, step := range steps {
+		switch {
+		case step.GameID == gameID:
+			inGame = true
+		case step.GameID == 0 && inGame:
+		default:
+			inGame = false
+			continue
+		}
+		step.Index = len(filtered)
+		filtered = append(filtered, step)
+	}
+	return filtered
+}
+
+// gameIDFromRaw pulls data.gameId out of a champ-select payload, if present.
+func gameIDFromRaw(raw json.RawMessage) int64 {
+	var arr []json.RawMessage
+	if err := json.Unmarshal(raw, &arr); err == nil && len(arr) >= 3 {
+		raw = arr[2]
+	}
+
+	var event struct {
+		Data struct {
+			GameID int64 `json:"gameId"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &event); err != nil {
+		return 0
+	}
+	return event.Data.GameID
+}
+
+// offsetAnchor derives the wall-clock time corresponding to offset zero from the
+// first event that carries both a timestamp and a monotonic offset.
+func offsetAnchor(events []CapturedEvent) (time.Time, bool) {
+	for _, ev := range events {
+		if ev.OffsetMs == nil {
+			continue
+		}
 		ts := parseTime(ev.Timestamp)
-		eventType, summary := summarize(ev.RawData)
-
-		steps = append(steps, Step{
-			Index:     idx,
-			Timestamp: ts,
-			Raw:       ev.RawData,
-			EventType: eventType,
-			Summary:   summary,
-		})
+		if ts.IsZero() {
+			continue
+		}
+		return ts.Add(-time.Duration(*ev.OffsetMs) * time.Millisecond), true
 	}
+	return time.Time{}, false
+}
 
-	return steps, nil
+// resolveOffsets rewrites event timestamps from their monotonic offsets and drops
+// the offsets, so events from captures with different start points can be mixed.
+func resolveOffsets(session *CaptureSession) {
+	anchor, ok := offsetAnchor(session.Events)
+	if !ok {
+		return
+	}
+	for i := range session.Events {
+		ev := &session.Events[i]
+		if ev.OffsetMs != nil {
+			ev.Timestamp = anchor.Add(time.Duration(*ev.OffsetMs) * time.Millisecond).Format(time.RFC3339Nano)
+			ev.OffsetMs = nil
+		}
+	}
+}
+
+// StepAt returns the index of the last step at or before t. It returns false
+// when t precedes every timestamped step.
+func StepAt(steps []Step, t time.Time) (int, bool) {
+	idx, found := 0, false
+	for i, step := range steps {
+		if step.Timestamp.IsZero() {
+			continue
+		}
+		if step.Timestamp.After(t) {
+			break
+		}
+		idx, found = i, true
+	}
+	return idx, found
 }
 
 func parseTime(raw string) time.Time {
@@ -73,8 +348,9 @@ func parseTime(raw string) time.Time {
 	return t
 }
 
-// summarize extracts a lightweight description for REPL printing.
-func summarize(raw json.RawMessage) (string, string) {
+// Summarize extracts a lightweight description for REPL printing, returning the
+// event type, summary line and timer phase (if any).
+func Summarize(raw json.RawMessage) (string, string, string) {
 	var arr []json.RawMessage
 	if err := json.Unmarshal(raw, &arr); err == nil && len(arr) >= 3 {
 		var name string
@@ -87,17 +363,23 @@ func summarize(raw json.RawMessage) (string, string) {
 		if eventType == "" {
 			eventType = stringFromMap(eventData, "type")
 		}
-		phase := ""
+		phase, queue := "", ""
 		if data, ok := eventData["data"].(map[string]any); ok {
 			if timer, ok := data["timer"].(map[string]any); ok {
 				phase = stringFromMap(timer, "phase")
 			}
+			if queueID, ok := data["queueId"].(float64); ok && queueID > 0 {
+				queue = QueueName(int(queueID))
+			}
 		}
 
 		summary := name
 		if eventType != "" {
 			summary += " | " + eventType
 		}
+		if queue != "" {
+			summary += " | " + queue
+		}
 		if phase != "" {
 			summary += " | phase=" + phase
 		}
@@ -105,10 +387,10 @@ func summarize(raw json.RawMessage) (string, string) {
 			summary = "event"
 		}
 
-		return eventType, summary
+		return eventType, summary, phase
 	}
 
-	// Handle map-shaped payloads (e.g., Delete marker appended by capturer).
+	// Handle map-shaped payloads (e.g., the synthetic Delete marker in older captures).
 	var obj map[string]any
 	if err := json.Unmarshal(raw, &obj); err == nil {
 		eventType := stringFromMap(obj, "eventType")
@@ -121,11 +403,11 @@ func summarize(raw json.RawMessage) (string, string) {
 			summary = "event"
 		}
 
-		return eventType, summary
+		return eventType, summary, ""
 	}
 
 	// Fallback for unexpected shapes.
-	return "unknown", "event"
+	return "unknown", "event", ""
 }
 
 func stringFromMap(m map[string]any, key string) string {
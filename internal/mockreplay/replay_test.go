@@ -0,0 +1,200 @@
+package mockreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// FuzzCaptureRoundTrip writes random event sequences with WriteCapture, the
+// writer the capture tool and the overlay's recorder use, and checks that
+// LoadCapture and StreamCapture both read back the same events and count.
+func FuzzCaptureRoundTrip(f *testing.F) {
+	f.Add(int64(1), uint8(0))
+	f.Add(int64(2), uint8(1))
+	f.Add(int64(3), uint8(17))
+	f.Add(int64(4), uint8(255))
+
+	f.Fuzz(func(t *testing.T, seed int64, n uint8) {
+		want := randomSession(rand.New(rand.NewSource(seed)), int(n))
+		path := filepath.Join(t.TempDir(), "capture.json")
+		if err := WriteCapture(path, want); err != nil {
+			t.Fatalf("WriteCapture: %v", err)
+		}
+
+		loaded, err := LoadCapture(path)
+		if err != nil {
+			t.Fatalf("LoadCapture: %v", err)
+		}
+		assertSession(t, "LoadCapture", loaded, want)
+
+		var streamed []CapturedEvent
+		header, err := StreamCapture(path, func(ev CapturedEvent) error {
+			streamed = append(streamed, ev)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("StreamCapture: %v", err)
+		}
+		header.Events = streamed
+		if header.Events == nil {
+			header.Events = []CapturedEvent{}
+		}
+		assertSession(t, "StreamCapture", header, want)
+	})
+}
+
+// TestStreamCaptureFromResumes grows a capture the way the capture tool does,
+// rewriting the whole file with more events and a longer header each time, and
+// checks that resuming from the returned offset yields exactly the new events.
+func TestStreamCaptureFromResumes(t *testing.T) {
+	full := randomSession(rand.New(rand.NewSource(7)), 120)
+	path := filepath.Join(t.TempDir(), "capture.json")
+
+	var offset int64
+	var got []CapturedEvent
+	for _, n := range []int{1, 2, 9, 10, 11, 11, 99, 100, 120} {
+		session := *full
+		session.Events = full.Events[:n]
+		session.EventCount = n
+		if n < len(full.Events) {
+			session.EndTime = ""
+		}
+		if err := WriteCapture(path, &session); err != nil {
+			t.Fatal(err)
+		}
+
+		var err error
+		before := len(got)
+		offset, err = StreamCaptureFrom(path, offset, func(ev CapturedEvent) error {
+			got = append(got, ev)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("StreamCaptureFrom at %d events: %v", n, err)
+		}
+		if len(got) != n {
+			t.Fatalf("after writing %d events, streamed %d (%d new)", n, len(got), len(got)-before)
+		}
+	}
+
+	for i := range full.Events {
+		if !reflect.DeepEqual(compactEvent(t, got[i]), compactEvent(t, full.Events[i])) {
+			t.Errorf("event %d = %+v, want %+v", i, got[i], full.Events[i])
+		}
+	}
+
+	// A different capture written over the file doesn't continue at the offset
+	if err := WriteCapture(path, randomSession(rand.New(rand.NewSource(8)), 200)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := StreamCaptureFrom(path, offset, func(CapturedEvent) error { return nil }); err == nil {
+		t.Error("StreamCaptureFrom read on into a rewritten capture")
+	}
+}
+
+func randomSession(r *rand.Rand, n int) *CaptureSession {
+	start := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+	session := &CaptureSession{
+		StartTime:  start.Format(time.RFC3339),
+		EndTime:    start.Add(time.Duration(n) * time.Second).Format(time.RFC3339),
+		EventCount: n,
+		Events:     make([]CapturedEvent, 0, n),
+	}
+	if r.Intn(2) == 0 {
+		session.Metadata = map[string]string{"patch": "14.9", "note": randomString(r)}
+	}
+
+	for i := 0; i < n; i++ {
+		offset := int64(i)*250 + r.Int63n(250)
+		ev := CapturedEvent{
+			Timestamp: start.Add(time.Duration(offset) * time.Millisecond).Format(time.RFC3339Nano),
+			GameID:    r.Int63n(1 << 53),
+			RawData:   randomPayload(r, i),
+		}
+		if r.Intn(2) == 0 {
+			ev.OffsetMs = &offset
+		}
+		if r.Intn(3) == 0 {
+			latency := r.Int63n(2000) - 1000
+			ev.ClientLatencyMs = &latency
+		}
+		if r.Intn(5) == 0 {
+			ev.Kind = "chat"
+		}
+		ev.GapCompressed = r.Intn(10) == 0
+		session.Events = append(session.Events, ev)
+	}
+	return session
+}
+
+func randomPayload(r *rand.Rand, counter int) json.RawMessage {
+	eventType := []string{"Create", "Update", "Delete"}[r.Intn(3)]
+	var data interface{}
+	if eventType != "Delete" || r.Intn(2) == 0 {
+		data = map[string]interface{}{
+			"counter":           counter,
+			"localPlayerCellId": r.Intn(10),
+			"myTeam":            []interface{}{map[string]interface{}{"cellId": r.Intn(5), "gameName": randomString(r)}},
+		}
+	}
+	raw, err := json.Marshal([]interface{}{8, "OnJsonApiEvent_lol-champ-select_v1_session", map[string]interface{}{
+		"eventType": eventType,
+		"uri":       "/lol-champ-select/v1/session",
+		"data":      data,
+	}})
+	if err != nil {
+		panic(err)
+	}
+	return raw
+}
+
+// randomString includes characters that need escaping in JSON
+func randomString(r *rand.Rand) string {
+	const alphabet = "abcXYZ019 ,:{}[]\"\\\n\té英雄"
+	runes := []rune(alphabet)
+	out := make([]rune, r.Intn(12))
+	for i := range out {
+		out[i] = runes[r.Intn(len(runes))]
+	}
+	return string(out)
+}
+
+func assertSession(t *testing.T, via string, got, want *CaptureSession) {
+	t.Helper()
+	if got.StartTime != want.StartTime || got.EndTime != want.EndTime {
+		t.Errorf("%s: times = %q..%q, want %q..%q", via, got.StartTime, got.EndTime, want.StartTime, want.EndTime)
+	}
+	if got.EventCount != want.EventCount {
+		t.Errorf("%s: eventCount = %d, want %d", via, got.EventCount, want.EventCount)
+	}
+	if len(got.Events) != got.EventCount {
+		t.Errorf("%s: %d events but eventCount %d", via, len(got.Events), got.EventCount)
+	}
+	if !reflect.DeepEqual(got.Metadata, want.Metadata) {
+		t.Errorf("%s: metadata = %v, want %v", via, got.Metadata, want.Metadata)
+	}
+	if len(got.Events) != len(want.Events) {
+		t.Fatalf("%s: got %d events, want %d", via, len(got.Events), len(want.Events))
+	}
+	for i := range want.Events {
+		if !reflect.DeepEqual(compactEvent(t, got.Events[i]), compactEvent(t, want.Events[i])) {
+			t.Errorf("%s: event %d = %+v, want %+v", via, i, got.Events[i], want.Events[i])
+		}
+	}
+}
+
+// compactEvent undoes the indentation WriteCapture applies inside rawData
+func compactEvent(t *testing.T, ev CapturedEvent) CapturedEvent {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, ev.RawData); err != nil {
+		t.Fatalf("compact rawData: %v", err)
+	}
+	ev.RawData = buf.Bytes()
+	return ev
+}
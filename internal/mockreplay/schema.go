@@ -0,0 +1,126 @@
+package mockreplay
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// FieldDiff is one difference between the champ-select payloads of two captures.
+// Path is dotted, with [] marking array elements, e.g. "myTeam[].championId".
+type FieldDiff struct {
+	Path   string
+	Change string // "added" (only in b), "removed" (only in a) or "type"
+	A      string // JSON type(s) seen in a, e.g. "number" or "number|string"; empty if absent
+	B      string // JSON type(s) seen in b
+}
+
+// String formats the diff for terminal output.
+func (d FieldDiff) String() string {
+	switch d.Change {
+	case "added":
+		return "+ " + d.Path + " (" + d.B + ")"
+	case "removed":
+		return "- " + d.Path + " (" + d.A + ")"
+	}
+	return "~ " + d.Path + " (" + d.A + " -> " + d.B + ")"
+}
+
+// CompareSchemas walks the champ-select data objects of both captures and
+// reports keys present in only one of them, and keys whose JSON type changed.
+// A null value doesn't count as a type, since many fields are null until set.
+func CompareSchemas(a, b *CaptureSession) []FieldDiff {
+	schemaA, schemaB := sessionSchema(a), sessionSchema(b)
+
+	var diffs []FieldDiff
+	for path, typesA := range schemaA {
+		typesB, ok := schemaB[path]
+		switch {
+		case !ok:
+			diffs = append(diffs, FieldDiff{Path: path, Change: "removed", A: typeList(typesA)})
+		case typeList(typesA) != typeList(typesB) && len(typesA) > 0 && len(typesB) > 0:
+			diffs = append(diffs, FieldDiff{Path: path, Change: "type", A: typeList(typesA), B: typeList(typesB)})
+		}
+	}
+	for path, typesB := range schemaB {
+		if _, ok := schemaA[path]; !ok {
+			diffs = append(diffs, FieldDiff{Path: path, Change: "added", B: typeList(typesB)})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Path < diffs[j].Path })
+	return diffs
+}
+
+// sessionSchema maps every field path in the session's champ-select data to the
+// set of non-null JSON types seen there.
+func sessionSchema(session *CaptureSession) map[string]map[string]bool {
+	schema := make(map[string]map[string]bool)
+	for _, ev := range session.Events {
+		if ev.Kind != "" {
+			continue
+		}
+		var arr []json.RawMessage
+		if err := json.Unmarshal(ev.RawData, &arr); err != nil || len(arr) < 3 {
+			continue
+		}
+		var event struct {
+			Data any `json:"data"`
+		}
+		if err := json.Unmarshal(arr[2], &event); err != nil {
+			continue
+		}
+		if data, ok := event.Data.(map[string]any); ok {
+			walkSchema(schema, "", data)
+		}
+	}
+	return schema
+}
+
+func walkSchema(schema map[string]map[string]bool, prefix string, v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, child := range v {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			if schema[path] == nil {
+				schema[path] = make(map[string]bool)
+			}
+			if t := jsonType(child); t != "null" {
+				schema[path][t] = true
+			}
+			walkSchema(schema, path, child)
+		}
+	case []any:
+		for _, item := range v {
+			walkSchema(schema, prefix+"[]", item)
+		}
+	}
+}
+
+func jsonType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	}
+	return "object"
+}
+
+func typeList(types map[string]bool) string {
+	list := make([]string, 0, len(types))
+	for t := range types {
+		list = append(list, t)
+	}
+	sort.Strings(list)
+	return strings.Join(list, "|")
+}
@@ -0,0 +1,142 @@
+package mockreplay
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SkinEvent is a player changing their selected champion skin or ward skin.
+type SkinEvent struct {
+	StepIndex int
+	Timestamp time.Time
+	CellID    int
+	IsAlly    bool
+	Kind      string // "skin" or "ward"
+	From      int
+	To        int
+	Summary   string
+}
+
+// skinKey identifies a player: the same cell id appears once on each team.
+type skinKey struct {
+	ally bool
+	cell int
+}
+
+// skinMember is the subset of a team member needed to follow skin choices.
+type skinMember struct {
+	CellID         int `json:"cellId"`
+	ChampionID     int `json:"championId"`
+	SelectedSkinID int `json:"selectedSkinId"`
+	WardSkinID     int `json:"wardSkinId"`
+}
+
+// SkinEvents derives skin and ward-skin changes from raw session updates. A
+// skin that changes along with the champion (a pick, swap or reroll) comes with
+// the new champion rather than from the player choosing it, so it is skipped.
+// Nothing is compared across a Delete or a change of game id, so back-to-back
+// champ selects in one capture don't report the next game's skins as changes.
+func SkinEvents(steps []Step) []SkinEvent {
+	var events []SkinEvent
+	prev := make(map[skinKey]skinMember)
+	var gameID int64
+
+	for _, step := range steps {
+		if strings.EqualFold(step.EventType, "Delete") {
+			clear(prev)
+			continue
+		}
+		if step.GameID != 0 {
+			if step.GameID != gameID {
+				clear(prev)
+			}
+			gameID = step.GameID
+		}
+
+		members, ok := stepSkinMembers(step.Raw)
+		if !ok {
+			continue
+		}
+
+		var changes []SkinEvent
+		for key, cur := range members {
+			old, seen := prev[key]
+			prev[key] = cur
+			if !seen {
+				continue
+			}
+			if old.SelectedSkinID != cur.SelectedSkinID && old.ChampionID == cur.ChampionID {
+				changes = append(changes, skinEvent(step, key, "skin", old.SelectedSkinID, cur.SelectedSkinID))
+			}
+			if old.WardSkinID != cur.WardSkinID {
+				changes = append(changes, skinEvent(step, key, "ward", old.WardSkinID, cur.WardSkinID))
+			}
+		}
+
+		// Map order is random; keep output stable
+		sort.Slice(changes, func(i, j int) bool {
+			if changes[i].CellID != changes[j].CellID {
+				return changes[i].CellID < changes[j].CellID
+			}
+			return changes[i].Kind < changes[j].Kind
+		})
+		events = append(events, changes...)
+	}
+
+	return events
+}
+
+// stepSkinMembers reads both teams from a [type, name, event] payload, keyed by
+// side and cell. It returns false for payloads without a session.
+func stepSkinMembers(raw json.RawMessage) (map[skinKey]skinMember, bool) {
+	var arr []json.RawMessage
+	if err := json.Unmarshal(raw, &arr); err != nil || len(arr) < 3 {
+		return nil, false
+	}
+
+	var event struct {
+		Data struct {
+			MyTeam    []skinMember `json:"myTeam"`
+			TheirTeam []skinMember `json:"theirTeam"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(arr[2], &event); err != nil {
+		return nil, false
+	}
+	if len(event.Data.MyTeam) == 0 && len(event.Data.TheirTeam) == 0 {
+		return nil, false
+	}
+
+	members := make(map[skinKey]skinMember)
+	for _, member := range event.Data.MyTeam {
+		members[skinKey{ally: true, cell: member.CellID}] = member
+	}
+	for _, member := range event.Data.TheirTeam {
+		members[skinKey{cell: member.CellID}] = member
+	}
+	return members, true
+}
+
+func skinEvent(step Step, key skinKey, kind string, from, to int) SkinEvent {
+	side := "enemy"
+	if key.ally {
+		side = "ally"
+	}
+	what := "skin"
+	if kind == "ward" {
+		what = "ward skin"
+	}
+	return SkinEvent{
+		StepIndex: step.Index,
+		Timestamp: step.Timestamp,
+		CellID:    key.cell,
+		IsAlly:    key.ally,
+		Kind:      kind,
+		From:      from,
+		To:        to,
+		Summary:   fmt.Sprintf("%s cell %d changed %s %d -> %d", side, key.cell, what, from, to),
+	}
+}
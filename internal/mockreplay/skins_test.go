@@ -0,0 +1,57 @@
+package mockreplay
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+// skinStep is a session update for gameID with one ally on championID wearing skinID
+func skinStep(index int, gameID int64, championID, skinID int) Step {
+	raw := fmt.Sprintf(`[8,"OnJsonApiEvent_lol-champ-select_v1_session",{"eventType":"Update","data":{"gameId":%d,"myTeam":[{"cellId":0,"championId":%d,"selectedSkinId":%d}]}}]`, gameID, championID, skinID)
+	return Step{Index: index, Raw: json.RawMessage(raw), EventType: "Update", GameID: gameID}
+}
+
+func deleteStep(index int) Step {
+	return Step{Index: index, Raw: json.RawMessage(`[8,"OnJsonApiEvent_lol-champ-select_v1_session",{"eventType":"Delete","data":null}]`), EventType: "Delete"}
+}
+
+func TestSkinEvents(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []Step
+		want  []int // step indexes with a skin change
+	}{
+		{
+			name:  "skin change in one game",
+			steps: []Step{skinStep(0, 1, 103, 103000), skinStep(1, 1, 103, 103001)},
+			want:  []int{1},
+		},
+		{
+			name:  "champion change brings its own skin",
+			steps: []Step{skinStep(0, 1, 103, 103000), skinStep(1, 1, 84, 84000)},
+		},
+		{
+			name:  "next game after a Delete",
+			steps: []Step{skinStep(0, 1, 103, 103000), deleteStep(1), skinStep(2, 2, 103, 103005)},
+		},
+		{
+			name:  "next game without a Delete",
+			steps: []Step{skinStep(0, 1, 103, 103000), skinStep(1, 2, 103, 103005), skinStep(2, 2, 103, 103006)},
+			want:  []int{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := SkinEvents(tt.steps)
+			var got []int
+			for _, event := range events {
+				got = append(got, event.StepIndex)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("skin changes at steps %v, want %v (%+v)", got, tt.want, events)
+			}
+		})
+	}
+}
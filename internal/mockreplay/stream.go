@@ -0,0 +1,200 @@
+package mockreplay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// StreamCapture reads a capture file one event at a time, calling fn for each
+// event in file order, so multi-hundred-MB captures can be processed without
+// holding them in memory. The returned session carries the header fields
+// (start and end time, event count, metadata); its Events are left nil. An error from fn
+// stops the stream and is returned as is.
+func StreamCapture(path string, fn func(CapturedEvent) error) (*CaptureSession, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read capture: %w", err)
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(f))
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, fmt.Errorf("parse capture: %w", err)
+	}
+
+	var session CaptureSession
+	sawEvents := false
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("parse capture: %w", err)
+		}
+		key, _ := tok.(string)
+
+		switch key {
+		case "startTime":
+			err = dec.Decode(&session.StartTime)
+		case "endTime":
+			err = dec.Decode(&session.EndTime)
+		case "eventCount":
+			err = dec.Decode(&session.EventCount)
+		case "metadata":
+			err = dec.Decode(&session.Metadata)
+		case "events":
+			sawEvents = true
+			if err := streamEvents(dec, fn); err != nil {
+				return nil, err
+			}
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse capture: %s: %w", key, err)
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, fmt.Errorf("parse capture: %w", err)
+	}
+
+	if session.StartTime == "" && !sawEvents {
+		return nil, fmt.Errorf("parse capture: %s is not a capture file (no startTime or events)", path)
+	}
+	return &session, nil
+}
+
+// StreamCaptureFrom calls fn for the events of a capture that is still being
+// written, starting at offset: 0 for the first event, or a value returned by an
+// earlier call on the same capture. It returns the offset to resume from next
+// time. Offsets count from the start of the events array, so a rewrite of the
+// whole file that only appends events and updates the header keeps them valid.
+// Only the header and the bytes after offset are read.
+func StreamCaptureFrom(path string, offset int64, fn func(CapturedEvent) error) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return offset, fmt.Errorf("read capture: %w", err)
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(f))
+	if err := expectDelim(dec, '{'); err != nil {
+		return offset, fmt.Errorf("parse capture: %w", err)
+	}
+	for {
+		if !dec.More() {
+			return offset, fmt.Errorf("parse capture: %s has no events array", path)
+		}
+		tok, err := dec.Token()
+		if err != nil {
+			return offset, fmt.Errorf("parse capture: %w", err)
+		}
+		if key, _ := tok.(string); key == "events" {
+			break
+		}
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return offset, fmt.Errorf("parse capture: %w", err)
+		}
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return offset, fmt.Errorf("parse capture: events: %w", err)
+	}
+	if tok == nil {
+		// "events": null; nothing captured yet
+		return offset, nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return offset, fmt.Errorf("parse capture: events: expected an array, got %v", tok)
+	}
+	start := dec.InputOffset()
+
+	if _, err := f.Seek(start+offset, io.SeekStart); err != nil {
+		return offset, fmt.Errorf("read capture: %w", err)
+	}
+	rest := bufio.NewReader(f)
+	skipped, err := skipToNextEvent(rest, offset > 0)
+	if err != nil {
+		return offset, fmt.Errorf("parse capture: events at offset %d: %w", offset, err)
+	}
+
+	// Re-open the array so the remaining events decode as its elements
+	events := json.NewDecoder(io.MultiReader(strings.NewReader("["), rest))
+	next := offset
+	err = streamEvents(events, func(ev CapturedEvent) error {
+		// Less one for the "[" put in front
+		next = offset + skipped + events.InputOffset() - 1
+		return fn(ev)
+	})
+	if err != nil {
+		return offset, err
+	}
+	return next, nil
+}
+
+// skipToNextEvent consumes whitespace, and the comma before the next element
+// when resuming after an earlier event, returning how many bytes it skipped.
+func skipToNextEvent(r *bufio.Reader, resuming bool) (int64, error) {
+	var skipped int64
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return skipped, err
+		}
+		switch {
+		case b == ' ' || b == '\n' || b == '\r' || b == '\t':
+			skipped++
+		case resuming && b == ',':
+			return skipped + 1, nil
+		case resuming && b != ']':
+			return skipped, fmt.Errorf("expected ',' or ']', got %q", b)
+		default:
+			return skipped, r.UnreadByte()
+		}
+	}
+}
+
+// streamEvents decodes the events array, handing each element to fn.
+func streamEvents(dec *json.Decoder, fn func(CapturedEvent) error) error {
+	// A capture written before any event arrived may have "events": null
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("parse capture: events: %w", err)
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("parse capture: events: expected an array, got %v", tok)
+	}
+
+	for i := 0; dec.More(); i++ {
+		var ev CapturedEvent
+		if err := dec.Decode(&ev); err != nil {
+			return fmt.Errorf("parse capture: event %d: %w", i, err)
+		}
+		if err := fn(ev); err != nil {
+			return err
+		}
+	}
+	if err := expectDelim(dec, ']'); err != nil {
+		return fmt.Errorf("parse capture: events: %w", err)
+	}
+	return nil
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("expected %q, got %v", want, tok)
+	}
+	return nil
+}
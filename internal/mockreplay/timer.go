@@ -0,0 +1,49 @@
+package mockreplay
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+)
+
+// InterpolateTimer returns a copy of a champ-select payload with its timer
+// advanced by elapsed: adjustedTimeLeftInPhase counts down, never below zero,
+// and internalNowInEpochMs moves forward to match. It returns false for
+// payloads without a running timer, such as Deletes and infinite phases.
+func InterpolateTimer(raw json.RawMessage, elapsed time.Duration) (json.RawMessage, bool) {
+	var payload []any
+	if err := json.Unmarshal(raw, &payload); err != nil || len(payload) < 3 {
+		return nil, false
+	}
+	event, ok := payload[2].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	data, ok := event["data"].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	timer, ok := data["timer"].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	if infinite, _ := timer["isInfinite"].(bool); infinite {
+		return nil, false
+	}
+	left, ok := timer["adjustedTimeLeftInPhase"].(float64)
+	if !ok || left <= 0 {
+		return nil, false
+	}
+
+	ms := float64(elapsed.Milliseconds())
+	timer["adjustedTimeLeftInPhase"] = math.Max(left-ms, 0)
+	if now, ok := timer["internalNowInEpochMs"].(float64); ok && now > 0 {
+		timer["internalNowInEpochMs"] = now + ms
+	}
+
+	out, err := json.Marshal(payload)
+	if err != nil {
+		return nil, false
+	}
+	return out, true
+}
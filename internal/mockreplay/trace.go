@@ -0,0 +1,77 @@
+package mockreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TraceEntry is one normalized step in a trace.
+type TraceEntry struct {
+	Index     int             `json:"index"`
+	Offset    string          `json:"offset"`
+	Timestamp string          `json:"timestamp"`
+	EventType string          `json:"eventType"`
+	Summary   string          `json:"summary"`
+	Raw       json.RawMessage `json:"raw"`
+}
+
+// Trace is a normalized, human-diffable view of a replay.
+type Trace struct {
+	Version int          `json:"version"`
+	Entries []TraceEntry `json:"entries"`
+}
+
+const traceVersion = 1
+
+// ToTrace renders steps as an indented trace with sorted keys and offsets relative
+// to the first step, so two captures can be compared with a plain text diff.
+func ToTrace(steps []Step) ([]byte, error) {
+	trace := Trace{Version: traceVersion, Entries: make([]TraceEntry, 0, len(steps))}
+
+	var origin time.Time
+	for _, step := range steps {
+		if !step.Timestamp.IsZero() {
+			origin = step.Timestamp
+			break
+		}
+	}
+
+	for _, step := range steps {
+		raw, err := normalizeJSON(step.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("step %d: %w", step.Index, err)
+		}
+
+		entry := TraceEntry{
+			Index:     step.Index,
+			EventType: step.EventType,
+			Summary:   step.Summary,
+			Raw:       raw,
+		}
+		if !step.Timestamp.IsZero() {
+			entry.Timestamp = step.Timestamp.Format(time.RFC3339Nano)
+			entry.Offset = "+" + step.Timestamp.Sub(origin).String()
+		}
+		trace.Entries = append(trace.Entries, entry)
+	}
+
+	return json.MarshalIndent(trace, "", "  ")
+}
+
+// normalizeJSON re-encodes raw JSON with object keys sorted and numbers preserved.
+func normalizeJSON(raw json.RawMessage) (json.RawMessage, error) {
+	if len(raw) == 0 {
+		return json.RawMessage("null"), nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
@@ -0,0 +1,36 @@
+package mockreplay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WriteCapture writes session as an indented capture file. session is usually
+// a *CaptureSession, but any value with the same JSON shape works, such as the
+// capture tool's own session type. The file is written to a temp file and
+// renamed into place, so readers never see a partial capture.
+func WriteCapture(path string, session interface{}) error {
+	if dir := filepath.Dir(path); dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("error creating output directory: %v", err)
+		}
+	}
+
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,79 @@
+package mockserver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Commands are the command names Exec understands, e.g. for tab completion.
+var Commands = []string{
+	"clients", "current", "draft", "help", "inspect", "jump", "next", "play",
+	"play-ramp", "prev", "quit", "reset", "rewind", "seekto", "send",
+	"sendraw", "skins", "stop",
+}
+
+// Exec runs one REPL command line against the server, printing its output,
+// and reports false for quit or exit.
+func (s *Server) Exec(line string) bool {
+	st := s.state
+	line = strings.TrimSpace(line)
+	switch {
+	case line == "", line == "help":
+		printHelp()
+	case line == "next":
+		st.advance(1, true)
+	case line == "prev":
+		st.advance(-1, true)
+	case strings.HasPrefix(line, "jump "):
+		st.jump(strings.TrimSpace(strings.TrimPrefix(line, "jump ")), true)
+	case strings.HasPrefix(line, "send "):
+		st.jump(strings.TrimSpace(strings.TrimPrefix(line, "send ")), true)
+	case strings.HasPrefix(line, "sendraw "):
+		st.sendRaw(strings.TrimSpace(strings.TrimPrefix(line, "sendraw ")))
+	case strings.HasPrefix(line, "seekto "):
+		st.seekTo(strings.TrimSpace(strings.TrimPrefix(line, "seekto ")))
+	case line == "play" || strings.HasPrefix(line, "play "):
+		st.play(strings.TrimSpace(strings.TrimPrefix(line, "play")), 1)
+	case strings.HasPrefix(line, "play-ramp "):
+		st.playRamp(strings.Fields(strings.TrimPrefix(line, "play-ramp ")))
+	case strings.HasPrefix(line, "rewind "):
+		st.play(strings.TrimSpace(strings.TrimPrefix(line, "rewind ")), -1)
+	case line == "stop":
+		st.stopPlayback()
+	case line == "reset":
+		st.setIndex(0, false)
+	case line == "inspect" || line == "current":
+		st.inspect()
+	case line == "draft":
+		st.printDraft()
+	case line == "skins":
+		st.printSkins()
+	case line == "clients":
+		st.printClients()
+	case line == "quit" || line == "exit":
+		return false
+	default:
+		fmt.Println("Unknown command, type 'help'")
+	}
+	return true
+}
+
+func printHelp() {
+	fmt.Println("Commands:")
+	fmt.Println("  next            advance to the next step and broadcast")
+	fmt.Println("  prev            go back one step and broadcast")
+	fmt.Println("  jump <n>        jump to step n (0-based) and broadcast")
+	fmt.Println("  send <n>        alias for jump")
+	fmt.Println("  sendraw <json>  broadcast a JSON frame as-is, e.g. an odd session shape")
+	fmt.Println("  seekto <time>   jump to the last step at or before an RFC3339 time or offset (e.g. 2m)")
+	fmt.Println("  play [ms]       auto-advance to the end, every ms or at captured timing")
+	fmt.Println("  play-ramp <a> <b> play at captured timing, speeding from ax to bx across the replay")
+	fmt.Println("  rewind <ms>     auto-step backward to step 0 every ms")
+	fmt.Println("  stop            stop play/rewind")
+	fmt.Println("  reset           reset index to 0 (no broadcast)")
+	fmt.Println("  inspect/current show current step summary")
+	fmt.Println("  draft           list completed picks and bans with their steps")
+	fmt.Println("  skins           list skin and ward skin changes with their steps")
+	fmt.Println("  clients         list connected websocket clients")
+	fmt.Println("  quit            exit")
+}
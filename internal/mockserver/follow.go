@@ -0,0 +1,141 @@
+package mockserver
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+
+	"rez/internal/mockreplay"
+)
+
+// Follow watches the capture file at path and broadcasts steps as the capturer
+// writes them, keeping only gameID's steps when it is non-zero. It blocks until
+// the watch fails, so run it in its own goroutine.
+func (s *Server) Follow(path string, gameID int64) {
+	s.state.follow(path, gameID)
+}
+
+// follower tracks how far follow has read into a growing capture
+type follower struct {
+	path    string
+	gameID  int64
+	offset  int64 // where the next event starts, from StreamCaptureFrom
+	size    int64 // file size at the last read
+	builder mockreplay.StepBuilder
+	steps   []mockreplay.Step // every step read so far, before filtering by game
+}
+
+// reset forgets what was read, so the next read starts from the top
+func (f *follower) reset() {
+	f.offset, f.size = 0, 0
+	f.builder = mockreplay.StepBuilder{}
+	f.steps = nil
+}
+
+// read parses the events written since the last read and returns their steps.
+// Nothing is kept if the read fails.
+func (f *follower) read(size int64) ([]mockreplay.Step, error) {
+	var added []mockreplay.Step
+	builder := f.builder
+	offset, err := mockreplay.StreamCaptureFrom(f.path, f.offset, func(ev mockreplay.CapturedEvent) error {
+		added = append(added, builder.Add(ev))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	f.offset, f.size, f.builder = offset, size, builder
+	f.steps = append(f.steps, added...)
+	return added, nil
+}
+
+// follow watches the capture file and appends steps as the capturer writes
+// them. The capturer rewrites the whole file on every event, but only appends
+// to the events array, so each change is read from where the last one ended.
+func (s *state) follow(path string, gameID int64) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Printf("follow: %v", err)
+		return
+	}
+	defer watcher.Close()
+
+	// Watch the directory: atomic writes replace the file, which drops a watch on the file itself
+	target := filepath.Clean(path)
+	if err := watcher.Add(filepath.Dir(target)); err != nil {
+		log.Printf("follow: %v", err)
+		return
+	}
+	fmt.Printf("Following %s for new events\n", path)
+
+	f := &follower{path: path, gameID: gameID}
+	s.reload(f)
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != target || !event.Has(fsnotify.Create|fsnotify.Write|fsnotify.Rename) {
+				continue
+			}
+			s.reload(f)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("follow: %v", err)
+		}
+	}
+}
+
+// reload reads the events written since the last reload and broadcasts their
+// steps. A file that shrank or no longer continues where the last read ended
+// was replaced, so it is read again from the top. Clients are only moved along
+// when they were already at the latest step, so scrubbing back through the
+// replay isn't interrupted.
+func (s *state) reload(f *follower) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return
+	}
+	if info.Size() < f.size {
+		f.reset()
+	}
+	added, err := f.read(info.Size())
+	if err != nil && f.offset > 0 {
+		f.reset()
+		added, err = f.read(info.Size())
+	}
+	if err != nil || len(added) == 0 {
+		// Caught mid-write; the next event will pick it up
+		return
+	}
+
+	steps := f.steps
+	if f.gameID != 0 {
+		steps = mockreplay.FilterByGame(steps, f.gameID)
+	}
+
+	s.mu.Lock()
+	known := len(s.steps)
+	if len(steps) <= known {
+		s.mu.Unlock()
+		return
+	}
+	atTail := s.current == known-1
+	s.steps = steps
+	s.mu.Unlock()
+
+	for idx := known; idx < len(steps); idx++ {
+		if atTail {
+			s.setIndex(idx, true)
+		} else {
+			fmt.Printf("captured step %d | %s\n", idx, steps[idx].Summary)
+		}
+	}
+}
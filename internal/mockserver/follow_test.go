@@ -0,0 +1,78 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"rez/internal/mockreplay"
+)
+
+// writeCapture writes n session updates for gameID, as the capturer does after its nth event
+func writeCapture(t *testing.T, path string, gameID int64, n int) {
+	t.Helper()
+	session := &mockreplay.CaptureSession{StartTime: "2024-05-01T18:00:00Z", EventCount: n}
+	for i := 0; i < n; i++ {
+		offset := int64(i) * 1000
+		raw := fmt.Sprintf(`[8,"OnJsonApiEvent_lol-champ-select_v1_session",{"eventType":"Update","uri":"/lol-champ-select/v1/session","data":{"gameId":%d,"counter":%d,"myTeam":[{"cellId":0}]}}]`, gameID, i)
+		session.Events = append(session.Events, mockreplay.CapturedEvent{
+			Timestamp: fmt.Sprintf("2024-05-01T18:00:%02dZ", i),
+			OffsetMs:  &offset,
+			RawData:   json.RawMessage(raw),
+		})
+	}
+	if err := mockreplay.WriteCapture(path, session); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// assertSteps checks got against the steps of a full load of path
+func assertSteps(t *testing.T, got []mockreplay.Step, path string) {
+	t.Helper()
+	_, want, err := LoadSteps(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %d steps, want %d matching a full load", len(got), len(want))
+	}
+}
+
+func TestFollowReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.json")
+	writeCapture(t, path, 1, 3)
+	_, steps, err := LoadSteps(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := New(steps, ServerOptions{Addr: "127.0.0.1:0"})
+	s := srv.state
+	s.setIndex(2, false)
+
+	f := &follower{path: path}
+	s.reload(f)
+	assertSteps(t, f.steps, path)
+	readTo := f.offset
+
+	writeCapture(t, path, 1, 12)
+	s.reload(f)
+	if f.offset <= readTo {
+		t.Errorf("offset %d did not move past %d", f.offset, readTo)
+	}
+	assertSteps(t, s.allSteps(), path)
+	if s.currentStep().Index != 11 {
+		t.Errorf("current step %d, want 11: a client at the tail follows new steps", s.currentStep().Index)
+	}
+
+	// A new, shorter capture in its place is read again from the top
+	writeCapture(t, path, 2, 1)
+	s.reload(f)
+	if len(f.steps) != 1 {
+		t.Errorf("after the file shrank, follower has %d steps, want 1", len(f.steps))
+	}
+	writeCapture(t, path, 2, 20)
+	s.reload(f)
+	assertSteps(t, s.allSteps(), path)
+}
@@ -0,0 +1,233 @@
+package mockserver
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"rez/internal/msgpack"
+)
+
+// sendBuffer is the number of queued frames a client may fall behind by before
+// it is considered stalled and disconnected.
+const sendBuffer = 16
+
+const (
+	// pongWait is how long a client may stay silent before it is pruned.
+	pongWait = 30 * time.Second
+	// pingPeriod must be shorter than pongWait so live clients always answer in time.
+	pingPeriod = pongWait * 9 / 10
+	writeWait  = 5 * time.Second
+)
+
+// Frame encodings a client can receive; see ServerOptions.Encoding and the
+// ?encoding= query parameter.
+const (
+	EncodingJSON    = "json"
+	EncodingMsgpack = "msgpack"
+)
+
+type client struct {
+	id         string // Short id for logs, e.g. "c3"
+	seq        int    // Connect order, starting at 1; the number in id
+	remoteAddr string
+	encoding   string // "json" or "msgpack"
+	conn       *websocket.Conn
+	send       chan []byte
+}
+
+type hub struct {
+	mu     sync.Mutex
+	conns  map[*websocket.Conn]*client
+	nextID int
+}
+
+func newHub() *hub {
+	return &hub{conns: make(map[*websocket.Conn]*client)}
+}
+
+// add registers a connection and starts its writer. remoteAddr comes from the
+// upgrade request and is only used for logging.
+func (h *hub) add(conn *websocket.Conn, remoteAddr, encoding string) *client {
+	h.mu.Lock()
+	h.nextID++
+	c := &client{
+		id:         fmt.Sprintf("c%d", h.nextID),
+		seq:        h.nextID,
+		remoteAddr: remoteAddr,
+		encoding:   encoding,
+		conn:       conn,
+		send:       make(chan []byte, sendBuffer),
+	}
+	h.conns[conn] = c
+	total := len(h.conns)
+	h.mu.Unlock()
+
+	log.Printf("client %s connected from %s, %s frames (%d total)", c.id, remoteAddr, encoding, total)
+	go h.writeLoop(c)
+	return c
+}
+
+func (h *hub) remove(conn *websocket.Conn) {
+	h.mu.Lock()
+	c, ok := h.conns[conn]
+	h.drop(conn)
+	total := len(h.conns)
+	h.mu.Unlock()
+	conn.Close()
+
+	if ok {
+		log.Printf("client %s (%s) disconnected (%d total)", c.id, c.remoteAddr, total)
+	}
+}
+
+// drop unregisters a client and stops its writer. Callers must hold h.mu.
+func (h *hub) drop(conn *websocket.Conn) {
+	if c, ok := h.conns[conn]; ok {
+		delete(h.conns, conn)
+		close(c.send)
+	}
+}
+
+// writeLoop is the only goroutine that writes to a client's connection. It also
+// sends periodic pings so dead clients are detected by the read deadline.
+func (h *hub) writeLoop(c *client) {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case payload, ok := <-c.send:
+			if !ok {
+				return
+			}
+			msgType := websocket.TextMessage
+			if c.encoding == EncodingMsgpack {
+				packed, err := msgpack.FromJSON(payload)
+				if err != nil {
+					log.Printf("msgpack encode for %s failed, sending JSON: %v", c.id, err)
+				} else {
+					msgType, payload = websocket.BinaryMessage, packed
+				}
+			}
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(msgType, payload); err != nil {
+				log.Printf("ws send to %s (%s) failed, dropping client: %v", c.id, c.remoteAddr, err)
+				h.remove(c.conn)
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				log.Printf("ws ping to %s (%s) failed, dropping client: %v", c.id, c.remoteAddr, err)
+				h.remove(c.conn)
+				return
+			}
+		}
+	}
+}
+
+// send queues a payload for a single client without blocking.
+func (h *hub) send(conn *websocket.Conn, payload []byte) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	c, ok := h.conns[conn]
+	if !ok {
+		return fmt.Errorf("client not connected")
+	}
+	select {
+	case c.send <- payload:
+		return nil
+	default:
+		h.drop(conn)
+		conn.Close()
+		return fmt.Errorf("client %s (%s) send buffer full", c.id, c.remoteAddr)
+	}
+}
+
+func (h *hub) broadcast(payload []byte) {
+	h.broadcastWhere(nil, payload)
+}
+
+// broadcastSince sends payload only to clients that connected after the client
+// numbered seq, returning how many were sent to.
+func (h *hub) broadcastSince(seq int, payload []byte) int {
+	return h.broadcastWhere(func(c *client) bool { return c.seq > seq }, payload)
+}
+
+// sendToID sends payload to the single client with the given id, e.g. "c3".
+func (h *hub) sendToID(id string, payload []byte) error {
+	if h.broadcastWhere(func(c *client) bool { return c.id == id }, payload) == 0 {
+		return fmt.Errorf("no client %q connected", id)
+	}
+	return nil
+}
+
+// lastSeq returns the connect number of the most recent client, or 0 if none has connected.
+func (h *hub) lastSeq() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.nextID
+}
+
+// broadcastWhere queues payload for every client match accepts (all when match
+// is nil) and returns how many were queued. Stalled clients are dropped.
+func (h *hub) broadcastWhere(match func(*client) bool, payload []byte) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	sent := 0
+	for conn, c := range h.conns {
+		if match != nil && !match(c) {
+			continue
+		}
+		select {
+		case c.send <- payload:
+			sent++
+		default:
+			log.Printf("ws client %s (%s) stalled, dropping client", c.id, c.remoteAddr)
+			h.drop(conn)
+			conn.Close()
+		}
+	}
+	return sent
+}
+
+// closeAll disconnects every client.
+func (h *hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for conn := range h.conns {
+		h.drop(conn)
+		conn.Close()
+	}
+}
+
+func (h *hub) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.conns)
+}
+
+// clientInfo is a copy of a client's details, safe to use without the hub lock.
+type clientInfo struct {
+	id         string
+	seq        int
+	remoteAddr string
+	encoding   string
+}
+
+// snapshot returns the connected clients in connect order.
+func (h *hub) snapshot() []clientInfo {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	clients := make([]clientInfo, 0, len(h.conns))
+	for _, c := range h.conns {
+		clients = append(clients, clientInfo{id: c.id, seq: c.seq, remoteAddr: c.remoteAddr, encoding: c.encoding})
+	}
+	sort.Slice(clients, func(i, j int) bool { return clients[i].seq < clients[j].seq })
+	return clients
+}
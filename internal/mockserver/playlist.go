@@ -0,0 +1,76 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"rez/internal/mockreplay"
+)
+
+const champSelectTopic = "OnJsonApiEvent_lol-champ-select_v1_session"
+
+// PlayPlaylist plays captures back to back with gap between them. It blocks
+// until the playlist finishes or playback is stopped.
+func (s *Server) PlayPlaylist(paths []string, gap time.Duration) {
+	s.state.playPlaylist(paths, gap)
+}
+
+// playPlaylist plays each capture to the end at its captured timing, closes the
+// champ select with a Delete, waits gap, then moves on to the next. A manual
+// stop (or any command that interrupts playback) ends the playlist.
+func (s *state) playPlaylist(paths []string, gap time.Duration) {
+	for i, path := range paths {
+		if i > 0 {
+			session, steps, err := LoadSteps(path)
+			if err != nil {
+				fmt.Printf("playlist: skipping %s: %v\n", path, err)
+				continue
+			}
+			s.replaceSteps(path, session, steps)
+		}
+
+		fmt.Printf("playlist [%d/%d]: playing %s\n", i+1, len(paths), path)
+		s.setIndex(0, true)
+		<-s.startPlayback(1, 0, nil)
+
+		steps := s.allSteps()
+		if s.currentStep().Index != len(steps)-1 {
+			fmt.Println("playlist stopped")
+			return
+		}
+
+		if !strings.EqualFold(steps[len(steps)-1].EventType, "Delete") {
+			s.hub.broadcast(deleteFrame())
+			fmt.Println("sent Delete")
+		}
+
+		if i < len(paths)-1 {
+			fmt.Printf("playlist: next capture in %s\n", gap)
+			time.Sleep(gap)
+		}
+	}
+	fmt.Println("playlist finished")
+}
+
+// replaceSteps swaps in a newly loaded capture and rewinds to its first step.
+func (s *state) replaceSteps(path string, session *mockreplay.CaptureSession, steps []mockreplay.Step) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.steps = steps
+	s.current = 0
+	s.capturePath = path
+	s.startedAt = session.StartTime
+	s.metadata = session.Metadata
+}
+
+// deleteFrame mimics the event the LCU sends when champ select ends.
+func deleteFrame() []byte {
+	frame, _ := json.Marshal([]any{8, champSelectTopic, map[string]any{
+		"data":      nil,
+		"eventType": "Delete",
+		"uri":       "/lol-champ-select/v1/session",
+	}})
+	return frame
+}
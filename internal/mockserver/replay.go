@@ -0,0 +1,456 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"rez/internal/mockreplay"
+)
+
+type state struct {
+	mu          sync.Mutex
+	steps       []mockreplay.Step
+	current     int
+	hub         *hub
+	capturePath string            // guarded by mu; changes as a playlist advances
+	startedAt   string            // guarded by mu
+	metadata    map[string]string // capture tags reported by /health, guarded by mu
+	wrapFrames  bool              // wrap broadcasts in {index, total, raw} instead of the bare payload
+	encoding    string            // default frame encoding for /ws clients
+	interpolate time.Duration     // rebroadcast interval for interpolated timer frames during play; 0 disables
+	maxGap      time.Duration     // longest wait across a flagged gap when playing at captured timing; 0 disables
+	playStop    chan struct{}     // closes to stop auto-play; nil when idle, guarded by mu
+}
+
+// printClients lists the connected websocket clients.
+func (s *state) printClients() {
+	clients := s.hub.snapshot()
+	fmt.Printf("%d client(s) connected\n", len(clients))
+	for _, c := range clients {
+		fmt.Printf("  %-4s %-21s %s\n", c.id, c.remoteAddr, c.encoding)
+	}
+}
+
+func (s *state) advance(delta int, broadcast bool) {
+	target := s.currentStep().Index + delta
+	s.setIndex(target, broadcast)
+}
+
+func (s *state) jump(raw string, broadcast bool) {
+	idx, err := strconv.Atoi(raw)
+	if err != nil {
+		fmt.Printf("invalid index %q: %v\n", raw, err)
+		return
+	}
+	s.setIndex(idx, broadcast)
+}
+
+// sendRaw broadcasts raw to every client without touching the replay position.
+// It must be valid JSON but is otherwise sent as typed (never wrapped).
+func (s *state) sendRaw(raw string) {
+	if !json.Valid([]byte(raw)) {
+		fmt.Println("not valid JSON; nothing sent")
+		return
+	}
+	sent := s.hub.broadcastWhere(nil, []byte(raw))
+	fmt.Printf("sent raw frame to %d client(s)\n", sent)
+}
+
+// seekTo broadcasts the last step at or before a time, given either as RFC3339
+// or as an offset from the first step such as "2m" or "1m30s".
+func (s *state) seekTo(raw string) {
+	var target time.Time
+	if offset, err := time.ParseDuration(raw); err == nil {
+		target = s.allSteps()[0].Timestamp.Add(offset)
+	} else if t, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+		target = t
+	} else {
+		fmt.Printf("invalid time %q (use RFC3339 or an offset like 2m30s)\n", raw)
+		return
+	}
+
+	idx, ok := mockreplay.StepAt(s.allSteps(), target)
+	if !ok {
+		fmt.Printf("no step at or before %s\n", target.Format(time.RFC3339))
+		return
+	}
+	s.setIndex(idx, true)
+}
+
+// play parses an interval and starts auto-play in the given direction
+// (1 forward, -1 backward). Forward play without an interval follows the
+// captured timing.
+func (s *state) play(raw string, direction int) {
+	var interval time.Duration
+	if raw != "" {
+		ms, err := strconv.Atoi(raw)
+		if err != nil || ms <= 0 {
+			fmt.Printf("invalid interval %q\n", raw)
+			return
+		}
+		interval = time.Duration(ms) * time.Millisecond
+	} else if direction < 0 {
+		fmt.Println("rewind needs an interval in ms")
+		return
+	}
+	s.startPlayback(direction, interval, nil)
+}
+
+// speedRamp scales captured timing during play-ramp, changing the multiplier
+// linearly from start to end across the steps left when playback began.
+type speedRamp struct {
+	start, end float64
+}
+
+// at returns the multiplier for progress in [0, 1].
+func (r *speedRamp) at(progress float64) float64 {
+	if r == nil {
+		return 1
+	}
+	return r.start + (r.end-r.start)*progress
+}
+
+// playRamp parses start and end speed multipliers and plays forward at
+// captured timing, e.g. "0.5 4" starts at half speed and ends 4x faster.
+func (s *state) playRamp(args []string) {
+	if len(args) != 2 {
+		fmt.Println("usage: play-ramp <startSpeed> <endSpeed>")
+		return
+	}
+	var speeds [2]float64
+	for i, arg := range args {
+		speed, err := strconv.ParseFloat(arg, 64)
+		if err != nil || speed <= 0 {
+			fmt.Printf("invalid speed %q\n", arg)
+			return
+		}
+		speeds[i] = speed
+	}
+	s.startPlayback(1, 0, &speedRamp{start: speeds[0], end: speeds[1]})
+}
+
+// startPlayback steps through the replay in direction, broadcasting each step,
+// until it reaches either end or is stopped. A non-nil ramp speeds up (or slows
+// down) captured timing as playback progresses. The returned channel closes
+// when playback ends either way.
+func (s *state) startPlayback(direction int, interval time.Duration, ramp *speedRamp) <-chan struct{} {
+	s.stopPlayback()
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	s.mu.Lock()
+	s.playStop = stop
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		defer s.finishPlayback(stop)
+		first := s.currentStep().Index
+		for {
+			current, steps := s.currentStep(), s.allSteps()
+			target := current.Index + direction
+			if target < 0 || target >= len(steps) {
+				fmt.Println("playback finished")
+				return
+			}
+
+			delay, speed := interval, 1.0
+			if delay == 0 {
+				delay = stepDelay(current, steps[target])
+				// Pauses the capture flagged (remakes, reconnects) would stall the
+				// replay at captured timing. The flag sits on the later of the two steps.
+				later := steps[target]
+				if direction < 0 {
+					later = current
+				}
+				if later.AfterGap && s.maxGap > 0 && delay > s.maxGap {
+					delay = s.maxGap
+				}
+				if ramp != nil {
+					progress := 1.0
+					if remaining := len(steps) - 1 - first; remaining > 0 {
+						progress = float64(target-first) / float64(remaining)
+					}
+					speed = ramp.at(progress)
+					delay = time.Duration(float64(delay) / speed)
+				}
+			}
+
+			if !s.waitStep(current, delay, speed, direction, stop) {
+				return
+			}
+			if s.setIndex(target, true) != nil {
+				return
+			}
+		}
+	}()
+	return done
+}
+
+// waitStep waits delay before playback moves past current, reporting false if
+// playback was stopped. With Interpolate set and playback moving forward, the
+// current step is rebroadcast every tick with its timer counted down (speed
+// times faster than real time), so clients see a smooth countdown instead of
+// one that jumps at each captured event.
+func (s *state) waitStep(current mockreplay.Step, delay time.Duration, speed float64, direction int, stop <-chan struct{}) bool {
+	deadline := time.After(delay)
+	var tick <-chan time.Time
+	if s.interpolate > 0 && direction > 0 {
+		ticker := time.NewTicker(s.interpolate)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	started := time.Now()
+	for {
+		select {
+		case <-stop:
+			return false
+		case <-deadline:
+			return true
+		case <-tick:
+			if raw, ok := mockreplay.InterpolateTimer(current.Raw, time.Duration(float64(time.Since(started))*speed)); ok {
+				synthetic := current
+				synthetic.Raw = raw
+				s.hub.broadcast(s.frame(synthetic))
+			}
+		}
+	}
+}
+
+// stepDelay is the captured gap between two steps, in either direction.
+func stepDelay(from, to mockreplay.Step) time.Duration {
+	if from.Timestamp.IsZero() || to.Timestamp.IsZero() {
+		return time.Second
+	}
+	delay := to.Timestamp.Sub(from.Timestamp)
+	if delay < 0 {
+		delay = -delay
+	}
+	return delay
+}
+
+func (s *state) stopPlayback() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.playStop != nil {
+		close(s.playStop)
+		s.playStop = nil
+	}
+}
+
+// finishPlayback clears the playback handle if it still belongs to this run.
+func (s *state) finishPlayback(stop chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.playStop == stop {
+		s.playStop = nil
+	}
+}
+
+func (s *state) setIndex(idx int, broadcast bool) error {
+	s.mu.Lock()
+	if idx < 0 || idx >= len(s.steps) {
+		err := fmt.Errorf("index out of range (0-%d)", len(s.steps)-1)
+		s.mu.Unlock()
+		fmt.Println(err)
+		return err
+	}
+	s.current = idx
+	s.mu.Unlock()
+	if broadcast {
+		s.broadcastCurrent()
+	} else {
+		s.inspect()
+	}
+	return nil
+}
+
+func (s *state) currentStep() mockreplay.Step {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.steps[s.current]
+}
+
+// allSteps returns the loaded steps. The slice is replaced, never modified,
+// when Follow picks up new events, so callers may keep it.
+func (s *state) allSteps() []mockreplay.Step {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.steps
+}
+
+func (s *state) broadcastCurrent() {
+	step := s.currentStep()
+	s.hub.broadcast(s.frame(step))
+	fmt.Printf("sent step %d | %s\n", step.Index, step.Summary)
+}
+
+func (s *state) sendCurrent(conn *websocket.Conn) error {
+	step := s.currentStep()
+	return s.hub.send(conn, s.frame(step))
+}
+
+// frame returns the bytes sent to clients for a step. The bare payload keeps
+// parity with the live LCU; the wrapped form adds the replay position.
+func (s *state) frame(step mockreplay.Step) []byte {
+	if !s.wrapFrames {
+		return step.Raw
+	}
+	wrapped, err := json.Marshal(struct {
+		Index int             `json:"index"`
+		Total int             `json:"total"`
+		Raw   json.RawMessage `json:"raw"`
+	}{
+		Index: step.Index,
+		Total: len(s.allSteps()),
+		Raw:   step.Raw,
+	})
+	if err != nil {
+		log.Printf("wrap step %d failed, sending bare payload: %v", step.Index, err)
+		return step.Raw
+	}
+	return wrapped
+}
+
+func (s *state) inspect() {
+	step := s.currentStep()
+	fmt.Printf("step %d @ %s | %s\n", step.Index, step.Timestamp.Format(time.RFC3339), step.Summary)
+}
+
+// printDraft prints the pick/ban timeline derived from the loaded steps.
+func (s *state) printDraft() {
+	events := mockreplay.ActionEvents(s.allSteps())
+	if len(events) == 0 {
+		fmt.Println("no completed picks or bans in this capture")
+		return
+	}
+	for _, ev := range events {
+		fmt.Printf("step %d @ %s | %s\n", ev.StepIndex, ev.Timestamp.Format(time.RFC3339), ev.Summary)
+	}
+}
+
+func (s *state) printSkins() {
+	events := mockreplay.SkinEvents(s.allSteps())
+	if len(events) == 0 {
+		fmt.Println("no skin changes in this capture")
+		return
+	}
+	for _, ev := range events {
+		fmt.Printf("step %d @ %s | %s\n", ev.StepIndex, ev.Timestamp.Format(time.RFC3339), ev.Summary)
+	}
+}
+
+// controlCommand is a request sent over the /control websocket, e.g. {"cmd":"jump","index":5}.
+type controlCommand struct {
+	Cmd   string `json:"cmd"`
+	Index int    `json:"index"`
+	// Client ("c3") or Since (a connect number) target a jump/send at some
+	// clients only; the shared replay position is left alone.
+	Client string `json:"client,omitempty"`
+	Since  int    `json:"since,omitempty"`
+}
+
+type controlState struct {
+	Index      int    `json:"index"`
+	Total      int    `json:"total"`
+	Summary    string `json:"summary"`
+	Timestamp  string `json:"timestamp"`
+	LastClient int    `json:"lastClient"` // connect number of the newest /ws client
+}
+
+type controlReply struct {
+	State *controlState `json:"state,omitempty"`
+	Error string        `json:"error,omitempty"`
+}
+
+// handleControl applies a control command and reports the resulting replay position.
+// It mirrors the REPL commands so a browser scrubber can drive the replay.
+func (s *state) handleControl(cmd controlCommand) controlReply {
+	if cmd.Client != "" || cmd.Since > 0 {
+		return s.handleTargetedControl(cmd)
+	}
+
+	var err error
+	switch cmd.Cmd {
+	case "next":
+		err = s.setIndex(s.currentStep().Index+1, true)
+	case "prev":
+		err = s.setIndex(s.currentStep().Index-1, true)
+	case "jump", "send":
+		err = s.setIndex(cmd.Index, true)
+	case "reset":
+		err = s.setIndex(0, false)
+	case "current", "inspect", "state":
+	default:
+		err = fmt.Errorf("unknown command %q", cmd.Cmd)
+	}
+	if err != nil {
+		return controlReply{Error: err.Error()}
+	}
+
+	return s.controlReplyFor(s.currentStep())
+}
+
+// handleTargetedControl sends one step to the clients a command targets, so a
+// scrubbing window can move through the replay without disturbing the others.
+func (s *state) handleTargetedControl(cmd controlCommand) controlReply {
+	if cmd.Cmd != "jump" && cmd.Cmd != "send" {
+		return controlReply{Error: fmt.Sprintf("%q cannot target clients; use jump or send", cmd.Cmd)}
+	}
+	steps := s.allSteps()
+	if cmd.Index < 0 || cmd.Index >= len(steps) {
+		return controlReply{Error: fmt.Sprintf("index out of range (0-%d)", len(steps)-1)}
+	}
+
+	step := steps[cmd.Index]
+	frame := s.frame(step)
+	if cmd.Client != "" {
+		if err := s.hub.sendToID(cmd.Client, frame); err != nil {
+			return controlReply{Error: err.Error()}
+		}
+	} else if s.hub.broadcastSince(cmd.Since, frame) == 0 {
+		return controlReply{Error: fmt.Sprintf("no clients connected after c%d", cmd.Since)}
+	}
+	return s.controlReplyFor(step)
+}
+
+func (s *state) controlReplyFor(step mockreplay.Step) controlReply {
+	return controlReply{State: &controlState{
+		Index:      step.Index,
+		Total:      len(s.allSteps()),
+		Summary:    step.Summary,
+		Timestamp:  step.Timestamp.Format(time.RFC3339Nano),
+		LastClient: s.hub.lastSeq(),
+	}}
+}
+
+// LoadSteps loads a capture file or directory and builds its replay steps.
+func LoadSteps(path string) (*mockreplay.CaptureSession, []mockreplay.Step, error) {
+	load := mockreplay.LoadCapture
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		load = mockreplay.LoadCaptureDir
+	}
+	session, err := load(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load capture: %v", err)
+	}
+	if err := session.CheckEvents(); err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", path, err)
+	}
+	steps, err := mockreplay.BuildSteps(session)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build steps: %v", err)
+	}
+	if len(steps) == 0 {
+		return nil, nil, fmt.Errorf("capture has no steps")
+	}
+	return session, steps, nil
+}
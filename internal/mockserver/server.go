@@ -0,0 +1,213 @@
+// Package mockserver replays champ-select captures to websocket clients the way
+// the LCU would send them. The mock-champ-select tool wraps it in a REPL; tests
+// and other programs can embed it directly, several at a time.
+package mockserver
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"rez/internal/mockreplay"
+)
+
+// ServerOptions configures a Server. Addr defaults to 127.0.0.1:18080 and
+// Encoding to JSON.
+type ServerOptions struct {
+	Addr        string
+	CapturePath string            // reported by /health
+	StartedAt   string            // capture start time, reported by /health
+	WrapFrames  bool              // send {index, total, raw} instead of the bare payload
+	Encoding    string            // EncodingJSON or EncodingMsgpack; clients may override with ?encoding=
+	Interpolate time.Duration     // resend interval for interpolated timer frames during play; 0 disables
+	MaxGap      time.Duration     // cap on the wait across a gapCompressed pause at captured timing; 0 disables
+	Metadata    map[string]string // capture tags, reported by /health
+}
+
+// Server replays capture steps to websocket clients. It can be driven with
+// Exec, or remotely through /control.
+type Server struct {
+	state    *state
+	http     *http.Server
+	listener net.Listener
+
+	closed    chan struct{} // closed by the first Shutdown
+	closeOnce sync.Once
+}
+
+// New creates a server for steps. It does not listen until Start.
+func New(steps []mockreplay.Step, opts ServerOptions) *Server {
+	if opts.Addr == "" {
+		opts.Addr = "127.0.0.1:18080"
+	}
+	if opts.Encoding == "" {
+		opts.Encoding = EncodingJSON
+	}
+	st := &state{
+		steps:       steps,
+		hub:         newHub(),
+		capturePath: opts.CapturePath,
+		startedAt:   opts.StartedAt,
+		metadata:    opts.Metadata,
+		wrapFrames:  opts.WrapFrames,
+		encoding:    opts.Encoding,
+		interpolate: opts.Interpolate,
+		maxGap:      opts.MaxGap,
+	}
+	return &Server{
+		state:  st,
+		closed: make(chan struct{}),
+		http: &http.Server{
+			Addr:              opts.Addr,
+			Handler:           st.routes(),
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+	}
+}
+
+// Start binds the listener and serves in the background until ctx is done or
+// Shutdown is called. Listen errors are returned; once Start returns, Addr
+// reports the bound address (useful with port 0).
+func (s *Server) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.http.Addr)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+
+	go func() {
+		if err := s.http.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("server error: %v", err)
+		}
+	}()
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Shutdown()
+		case <-s.closed:
+		}
+	}()
+	return nil
+}
+
+// Addr returns the address the server is listening on, or the configured
+// address before Start.
+func (s *Server) Addr() string {
+	if s.listener != nil {
+		return s.listener.Addr().String()
+	}
+	return s.http.Addr
+}
+
+// Shutdown stops auto-play, disconnects clients and closes the listener. It is
+// safe to call more than once.
+func (s *Server) Shutdown() error {
+	s.closeOnce.Do(func() { close(s.closed) })
+	s.state.stopPlayback()
+	s.state.hub.closeAll()
+	return s.http.Close()
+}
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// routes returns a mux serving this state's endpoints. Each server gets its own
+// mux rather than the process-wide default, so several can run side by side.
+func (s *state) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", s.serveWS)
+	mux.HandleFunc("/control", s.serveControl)
+	mux.HandleFunc("/health", s.serveHealth)
+	return mux
+}
+
+func (s *state) serveWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("upgrade failed: %v", err)
+		return
+	}
+	clientEncoding := s.encoding
+	if requested := r.URL.Query().Get("encoding"); requested == EncodingJSON || requested == EncodingMsgpack {
+		clientEncoding = requested
+	}
+	c := s.hub.add(conn, r.RemoteAddr, clientEncoding)
+
+	// push the current step immediately so new clients see state
+	if err := s.sendCurrent(conn); err != nil {
+		log.Printf("initial send to %s failed: %v", c.id, err)
+		s.hub.remove(conn)
+		return
+	}
+
+	// keep connection alive; pongs extend the read deadline so silent
+	// clients are pruned once it lapses
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+	s.hub.remove(conn)
+}
+
+func (s *state) serveControl(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("control upgrade failed: %v", err)
+		return
+	}
+	defer conn.Close()
+	log.Printf("control client connected")
+
+	for {
+		var cmd controlCommand
+		if err := conn.ReadJSON(&cmd); err != nil {
+			if _, ok := err.(*json.SyntaxError); ok {
+				_ = conn.WriteJSON(controlReply{Error: "invalid JSON command"})
+				continue
+			}
+			break
+		}
+		if err := conn.WriteJSON(s.handleControl(cmd)); err != nil {
+			break
+		}
+	}
+	log.Printf("control client disconnected")
+}
+
+func (s *state) serveHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	current := s.currentStep()
+	s.mu.Lock()
+	capture, started, metadata := s.capturePath, s.startedAt, s.metadata
+	s.mu.Unlock()
+	payload := struct {
+		Steps       int               `json:"steps"`
+		Current     int               `json:"current"`
+		Summary     string            `json:"summary"`
+		Capture     string            `json:"capture"`
+		StartedAt   string            `json:"started"`
+		Metadata    map[string]string `json:"metadata,omitempty"`
+		CurrentSent string            `json:"currentStepTimestamp"`
+	}{
+		Steps:       len(s.allSteps()),
+		Current:     current.Index,
+		Summary:     current.Summary,
+		Capture:     capture,
+		StartedAt:   started,
+		Metadata:    metadata,
+		CurrentSent: current.Timestamp.Format(time.RFC3339),
+	}
+	_ = json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,112 @@
+package mockserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"rez/internal/mockreplay"
+)
+
+// testSteps builds a one-step replay whose session carries gameID
+func testSteps(t *testing.T, gameID int64) []mockreplay.Step {
+	t.Helper()
+	raw := fmt.Sprintf(`[8,"OnJsonApiEvent_lol-champ-select_v1_session",{"eventType":"Update","uri":"/lol-champ-select/v1/session","data":{"gameId":%d,"localPlayerCellId":0,"myTeam":[{"cellId":0}]}}]`, gameID)
+	steps, err := mockreplay.BuildSteps(&mockreplay.CaptureSession{
+		StartTime:  "2024-05-01T18:00:00Z",
+		EventCount: 1,
+		Events:     []mockreplay.CapturedEvent{{Timestamp: "2024-05-01T18:00:00Z", RawData: json.RawMessage(raw)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return steps
+}
+
+func TestServersSideBySide(t *testing.T) {
+	var servers []*Server
+	for _, gameID := range []int64{101, 202} {
+		srv := New(testSteps(t, gameID), ServerOptions{Addr: "127.0.0.1:0", CapturePath: fmt.Sprintf("game-%d.json", gameID)})
+		if err := srv.Start(context.Background()); err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+		defer srv.Shutdown()
+		servers = append(servers, srv)
+	}
+	if servers[0].Addr() == servers[1].Addr() {
+		t.Fatalf("both servers bound %s", servers[0].Addr())
+	}
+
+	for i, gameID := range []int64{101, 202} {
+		srv := servers[i]
+
+		conn, _, err := websocket.DefaultDialer.Dial("ws://"+srv.Addr()+"/ws", nil)
+		if err != nil {
+			t.Fatalf("dial %s: %v", srv.Addr(), err)
+		}
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, frame, err := conn.ReadMessage()
+		conn.Close()
+		if err != nil {
+			t.Fatalf("read initial frame from %s: %v", srv.Addr(), err)
+		}
+		var payload []json.RawMessage
+		if err := json.Unmarshal(frame, &payload); err != nil || len(payload) < 3 {
+			t.Fatalf("initial frame %s is not [type, name, event]", frame)
+		}
+		var event struct {
+			Data struct {
+				GameID int64 `json:"gameId"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(payload[2], &event); err != nil {
+			t.Fatal(err)
+		}
+		if event.Data.GameID != gameID {
+			t.Errorf("server %d sent game %d, want %d", i, event.Data.GameID, gameID)
+		}
+
+		resp, err := http.Get("http://" + srv.Addr() + "/health")
+		if err != nil {
+			t.Fatalf("health: %v", err)
+		}
+		var health struct {
+			Steps   int    `json:"steps"`
+			Capture string `json:"capture"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&health)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("game-%d.json", gameID); health.Steps != 1 || health.Capture != want {
+			t.Errorf("server %d health = %+v, want 1 step of %s", i, health, want)
+		}
+	}
+}
+
+func TestShutdownStopsContextWatcher(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	srv := New(testSteps(t, 1), ServerOptions{Addr: "127.0.0.1:0"})
+	// A context that is never cancelled: only Shutdown can end the watcher
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	srv.Shutdown()
+	srv.Shutdown() // safe to repeat
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines still running after Shutdown, want %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,322 @@
+// Package msgpack converts the JSON payloads exchanged with the mock server to
+// and from MessagePack. It covers the subset of the format JSON can express:
+// nil, booleans, integers, floats, strings, arrays and string-keyed maps.
+package msgpack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// FromJSON re-encodes a JSON document as MessagePack. Whole numbers stay
+// integers so large ids such as gameId survive the round trip exactly.
+func FromJSON(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("msgpack: parse json: %w", err)
+	}
+	return Marshal(v)
+}
+
+// ToJSON decodes a MessagePack document and returns it as JSON.
+func ToJSON(data []byte) ([]byte, error) {
+	v, err := Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// Marshal encodes v, which must be built from the types encoding/json produces
+// (with or without UseNumber) or that Unmarshal returns.
+func Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encode(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes a single MessagePack value into JSON-compatible Go types:
+// nil, bool, int64, uint64, float64, string, []any and map[string]any.
+func Unmarshal(data []byte) (any, error) {
+	d := &decoder{data: data}
+	v, err := d.value()
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("msgpack: %d trailing bytes", len(d.data)-d.pos)
+	}
+	return v, nil
+}
+
+func encode(buf *bytes.Buffer, v any) error {
+	switch v := v.(type) {
+	case nil:
+		buf.WriteByte(0xc0)
+	case bool:
+		if v {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			encodeInt(buf, n)
+			return nil
+		}
+		if u, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			encodeUint(buf, u)
+			return nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return fmt.Errorf("msgpack: bad number %q", v)
+		}
+		encodeFloat(buf, f)
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			encodeInt(buf, int64(v))
+		} else {
+			encodeFloat(buf, v)
+		}
+	case int:
+		encodeInt(buf, int64(v))
+	case int64:
+		encodeInt(buf, v)
+	case uint64:
+		encodeUint(buf, v)
+	case string:
+		encodeString(buf, v)
+	case []any:
+		encodeLength(buf, len(v), 0x90, 15, 0xdc, 0xdd)
+		for _, item := range v {
+			if err := encode(buf, item); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		encodeLength(buf, len(v), 0x80, 15, 0xde, 0xdf)
+		// Sorted keys keep the output stable for identical input
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			encodeString(buf, key)
+			if err := encode(buf, v[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("msgpack: unsupported type %T", v)
+	}
+	return nil
+}
+
+func encodeInt(buf *bytes.Buffer, n int64) {
+	switch {
+	case n >= 0 && n <= 0x7f:
+		buf.WriteByte(byte(n))
+	case n < 0 && n >= -32:
+		buf.WriteByte(byte(n))
+	case n >= math.MinInt8 && n <= math.MaxInt8:
+		buf.Write([]byte{0xd0, byte(n)})
+	case n >= math.MinInt16 && n <= math.MaxInt16:
+		buf.WriteByte(0xd1)
+		binary.Write(buf, binary.BigEndian, int16(n))
+	case n >= math.MinInt32 && n <= math.MaxInt32:
+		buf.WriteByte(0xd2)
+		binary.Write(buf, binary.BigEndian, int32(n))
+	default:
+		buf.WriteByte(0xd3)
+		binary.Write(buf, binary.BigEndian, n)
+	}
+}
+
+// encodeUint writes u as an int when it fits, and as a uint64 above MaxInt64
+func encodeUint(buf *bytes.Buffer, u uint64) {
+	if u <= math.MaxInt64 {
+		encodeInt(buf, int64(u))
+		return
+	}
+	buf.WriteByte(0xcf)
+	binary.Write(buf, binary.BigEndian, u)
+}
+
+func encodeFloat(buf *bytes.Buffer, f float64) {
+	buf.WriteByte(0xcb)
+	binary.Write(buf, binary.BigEndian, f)
+}
+
+func encodeString(buf *bytes.Buffer, s string) {
+	if len(s) <= 31 {
+		buf.WriteByte(0xa0 | byte(len(s)))
+	} else if len(s) <= math.MaxUint8 {
+		buf.Write([]byte{0xd9, byte(len(s))})
+	} else {
+		encodeLength(buf, len(s), 0, -1, 0xda, 0xdb)
+	}
+	buf.WriteString(s)
+}
+
+// encodeLength writes a container header: the fix form when n <= fixMax, else
+// the 16- or 32-bit form.
+func encodeLength(buf *bytes.Buffer, n int, fix byte, fixMax int, tag16, tag32 byte) {
+	switch {
+	case n <= fixMax:
+		buf.WriteByte(fix | byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(tag16)
+		binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(tag32)
+		binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+}
+
+var errShort = errors.New("msgpack: unexpected end of data")
+
+type decoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *decoder) take(n int) ([]byte, error) {
+	if n < 0 || d.pos+n > len(d.data) {
+		return nil, errShort
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// uint reads an n-byte big-endian unsigned integer
+func (d *decoder) uint(n int) (uint64, error) {
+	b, err := d.take(n)
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v, nil
+}
+
+func (d *decoder) value() (any, error) {
+	b, err := d.take(1)
+	if err != nil {
+		return nil, err
+	}
+	tag := b[0]
+
+	switch {
+	case tag <= 0x7f:
+		return int64(tag), nil
+	case tag >= 0xe0:
+		return int64(int8(tag)), nil
+	case tag&0xe0 == 0xa0:
+		return d.str(int(tag & 0x1f))
+	case tag&0xf0 == 0x90:
+		return d.array(int(tag & 0x0f))
+	case tag&0xf0 == 0x80:
+		return d.object(int(tag & 0x0f))
+	}
+
+	switch tag {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return d.uint(1 << (tag - 0xcc))
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (tag - 0xd0)
+		u, err := d.uint(size)
+		if err != nil {
+			return nil, err
+		}
+		// Sign-extend from the encoded width
+		shift := 64 - 8*size
+		return int64(u<<shift) >> shift, nil
+	case 0xca:
+		u, err := d.uint(4)
+		return float64(math.Float32frombits(uint32(u))), err
+	case 0xcb:
+		u, err := d.uint(8)
+		return math.Float64frombits(u), err
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (tag - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.str(int(n))
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (tag - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.array(int(n))
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (tag - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.object(int(n))
+	}
+	return nil, fmt.Errorf("msgpack: unsupported type byte 0x%02x at offset %d", tag, d.pos-1)
+}
+
+func (d *decoder) str(n int) (string, error) {
+	b, err := d.take(n)
+	return string(b), err
+}
+
+func (d *decoder) array(n int) ([]any, error) {
+	if n > len(d.data)-d.pos {
+		return nil, errShort
+	}
+	arr := make([]any, 0, n)
+	for i := 0; i < n; i++ {
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, v)
+	}
+	return arr, nil
+}
+
+func (d *decoder) object(n int) (map[string]any, error) {
+	if n > len(d.data)-d.pos {
+		return nil, errShort
+	}
+	obj := make(map[string]any, n)
+	for i := 0; i < n; i++ {
+		key, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		name, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf("msgpack: map key is %T, not a string", key)
+		}
+		if obj[name], err = d.value(); err != nil {
+			return nil, err
+		}
+	}
+	return obj, nil
+}
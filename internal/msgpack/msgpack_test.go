@@ -0,0 +1,199 @@
+package msgpack
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	reference "github.com/vmihailenco/msgpack/v5"
+)
+
+// roundTripCases covers every type the package encodes, at the size
+// boundaries where the wire format switches headers.
+func roundTripCases() []struct {
+	name  string
+	value any
+	tag   byte // expected first byte of our encoding
+} {
+	list := func(n int) []any {
+		arr := make([]any, n)
+		for i := range arr {
+			arr[i] = int64(i)
+		}
+		return arr
+	}
+	object := func(n int) map[string]any {
+		obj := make(map[string]any, n)
+		for i := 0; i < n; i++ {
+			obj[fmt.Sprintf("k%02d", i)] = int64(i)
+		}
+		return obj
+	}
+
+	return []struct {
+		name  string
+		value any
+		tag   byte
+	}{
+		{"nil", nil, 0xc0},
+		{"false", false, 0xc2},
+		{"true", true, 0xc3},
+
+		{"positive fixint", int64(127), 0x7f},
+		{"negative fixint -1", int64(-1), 0xff},
+		{"negative fixint -32", int64(-32), 0xe0},
+		{"int8 -33", int64(-33), 0xd0},
+		{"int8 min", int64(math.MinInt8), 0xd0},
+		{"int16 min", int64(math.MinInt16), 0xd1},
+		{"int32 min", int64(math.MinInt32), 0xd2},
+		{"int64 min", int64(math.MinInt64), 0xd3},
+		{"int64 max", int64(math.MaxInt64), 0xd3},
+		{"uint64 above int64", uint64(math.MaxInt64) + 1, 0xcf},
+		{"uint64 max", uint64(math.MaxUint64), 0xcf},
+		{"game id", int64(7123456789), 0xd3},
+
+		{"float64", 3.25, 0xcb},
+		{"negative float64", -0.5, 0xcb},
+		{"smallest float64", math.SmallestNonzeroFloat64, 0xcb},
+		{"largest float64", math.MaxFloat64, 0xcb},
+
+		{"empty string", "", 0xa0},
+		{"fixstr max", strings.Repeat("a", 31), 0xbf},
+		{"str8 min", strings.Repeat("a", 32), 0xd9},
+		{"str8 max", strings.Repeat("a", math.MaxUint8), 0xd9},
+		{"str16 min", strings.Repeat("a", math.MaxUint8+1), 0xda},
+		{"str16 max", strings.Repeat("a", math.MaxUint16), 0xda},
+		{"str32 min", strings.Repeat("a", math.MaxUint16+1), 0xdb},
+		{"utf-8 string", "Nunu & Willump – 努努", 0xb9},
+
+		{"empty array", []any{}, 0x90},
+		{"fixarray max", list(15), 0x9f},
+		{"array16 min", list(16), 0xdc},
+		{"array16 max", list(math.MaxUint16), 0xdc},
+		{"array32 min", list(math.MaxUint16 + 1), 0xdd},
+
+		{"empty map", map[string]any{}, 0x80},
+		{"fixmap max", object(15), 0x8f},
+		{"map16 min", object(16), 0xde},
+
+		{"nested maps", map[string]any{
+			"gameId": int64(7123456789),
+			"timer":  map[string]any{"phase": "BAN_PICK", "adjustedTimeLeftInPhase": int64(29871), "isInfinite": false},
+			"myTeam": []any{
+				map[string]any{"cellId": int64(0), "championId": int64(103), "assignedPosition": "middle"},
+				map[string]any{"cellId": int64(1), "championId": int64(0), "nameVisibilityType": nil},
+			},
+			"actions": []any{[]any{map[string]any{"id": int64(1), "completed": true, "pickTurn": int64(-1)}}},
+			"bench":   map[string]any{"rates": map[string]any{"win": 0.51}},
+		}, 0x85},
+	}
+}
+
+func TestMarshalDecodesWithReference(t *testing.T) {
+	for _, tt := range roundTripCases() {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if data[0] != tt.tag {
+				t.Errorf("header 0x%02x, want 0x%02x", data[0], tt.tag)
+			}
+
+			dec := reference.NewDecoder(bytes.NewReader(data))
+			dec.UseLooseInterfaceDecoding(true)
+			got, err := dec.DecodeInterface()
+			if err != nil {
+				t.Fatalf("reference decode: %v", err)
+			}
+			if !reflect.DeepEqual(normalize(got), normalize(tt.value)) {
+				t.Errorf("reference decoded %v, want %v", summarize(got), summarize(tt.value))
+			}
+		})
+	}
+}
+
+func TestUnmarshalReadsReference(t *testing.T) {
+	for _, compact := range []bool{false, true} {
+		for _, tt := range roundTripCases() {
+			t.Run(fmt.Sprintf("%s/compact=%t", tt.name, compact), func(t *testing.T) {
+				var buf bytes.Buffer
+				enc := reference.NewEncoder(&buf)
+				enc.UseCompactInts(compact)
+				if err := enc.Encode(tt.value); err != nil {
+					t.Fatalf("reference encode: %v", err)
+				}
+
+				got, err := Unmarshal(buf.Bytes())
+				if err != nil {
+					t.Fatalf("Unmarshal: %v", err)
+				}
+				if !reflect.DeepEqual(normalize(got), normalize(tt.value)) {
+					t.Errorf("decoded %v, want %v", summarize(got), summarize(tt.value))
+				}
+			})
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	for _, doc := range []string{
+		`null`,
+		`[8,"OnJsonApiEvent_lol-champ-select_v1_session",{"data":{"gameId":7123456789,"myTeam":[{"cellId":0}]},"eventType":"Update"}]`,
+		`{"max":18446744073709551615,"min":-9223372036854775808,"rate":0.125}`,
+	} {
+		packed, err := FromJSON([]byte(doc))
+		if err != nil {
+			t.Fatalf("FromJSON(%s): %v", doc, err)
+		}
+		back, err := ToJSON(packed)
+		if err != nil {
+			t.Fatalf("ToJSON(%s): %v", doc, err)
+		}
+		if string(back) != doc {
+			t.Errorf("round trip of %s gave %s", doc, back)
+		}
+	}
+}
+
+// normalize maps the integer and float widths the two decoders choose onto
+// int64, uint64 (only above MaxInt64) and float64 so results compare equal.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case int8, int16, int32, int, int64:
+		return reflect.ValueOf(v).Int()
+	case uint8, uint16, uint32, uint, uint64:
+		u := reflect.ValueOf(v).Uint()
+		if u <= math.MaxInt64 {
+			return int64(u)
+		}
+		return u
+	case float32:
+		return float64(v)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = normalize(item)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = normalize(item)
+		}
+		return out
+	}
+	return v
+}
+
+// summarize keeps failure messages readable for the large boundary values
+func summarize(v any) string {
+	s := fmt.Sprintf("%T %v", v, v)
+	if len(s) > 120 {
+		return s[:120] + "..."
+	}
+	return s
+}
@@ -4,6 +4,7 @@ import (
 	"embed"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/joho/godotenv"
@@ -15,6 +16,9 @@ import (
 //go:embed all:frontend/dist
 var assets embed.FS
 
+// singleInstanceID names the lock shared by every running rez (a named mutex on Windows)
+const singleInstanceID = "c6f1f0de-rez-league-overlay"
+
 func main() {
 	_ = godotenv.Load(".env") // optional error check
 	mockEnabled := envBool("MOCK_CHAMP_SELECT")
@@ -23,20 +27,54 @@ func main() {
 		mockWS = "ws://127.0.0.1:18080/ws"
 	}
 
-	app := NewApp(mockEnabled, mockWS)
+	// MOCK_ENCODING=msgpack asks the mock server for compact binary frames
+	mockEnc := os.Getenv("MOCK_ENCODING")
+
+	// RECORD_TO is a directory; in live mode each champ select is also written there as a capture
+	recordTo := os.Getenv("RECORD_TO")
+
+	// REZ_LOCKFILE_WATCH=poll (or auto) for installs where fsnotify misses the lockfile
+	watchMode, err := ParseWatchMode(os.Getenv("REZ_LOCKFILE_WATCH"))
+	if err != nil {
+		log.Printf("ignoring REZ_LOCKFILE_WATCH: %v", err)
+	}
+
+	// REZ_SHOW_IN_TASKBAR=1 keeps the overlay alt-tabbable instead of a hidden tool window
+	showInTaskbar := envBool("REZ_SHOW_IN_TASKBAR")
+
+	// Overlay positioning defaults; unset values keep the built-in layout
+	monitorOpts := MonitorOptions{
+		DockSide: os.Getenv("REZ_DOCK_SIDE"),
+		Width:    envInt("REZ_OVERLAY_WIDTH", overlayWidth),
+		Gap:      envInt("REZ_OVERLAY_GAP", 0),
+	}
+
+	app := NewApp(mockEnabled, mockWS, mockEnc, recordTo, watchMode, showInTaskbar, monitorOpts)
+	// REZ_COMPANION_WINDOWS is a comma-separated list of window titles (or parts of them)
+	// that keep the overlay shown while focused, e.g. a build guide
+	app.SetCompanionWindows(strings.Split(os.Getenv("REZ_COMPANION_WINDOWS"), ","))
 	log.Println("Mock enabled:", mockEnabled)
+	if recordTo != "" && !mockEnabled {
+		log.Println("Recording champ select to:", recordTo)
+	}
 
 	// Create application with options
-	err := wails.Run(&options.App{
+	err = wails.Run(&options.App{
 		Title:  "rez - League Overlay",
-		Width:  400,
+		Width:  app.monitorOptions().Width,
 		Height: 800, // Will be resized to match LoL client height
 		AssetServer: &assetserver.Options{
 			Assets: assets,
 		},
 		BackgroundColour: &options.RGBA{R: 0, G: 0, B: 0, A: 0},
 		OnStartup:        app.startup,
+		OnShutdown:       app.shutdown,
 		Frameless:        true, // Keep frameless for clean overlay look
+		// Two overlays would both find the same window by title and fight over its position
+		SingleInstanceLock: &options.SingleInstanceLock{
+			UniqueId:               singleInstanceID,
+			OnSecondInstanceLaunch: app.onSecondInstanceLaunch,
+		},
 		Bind: []interface{}{
 			app,
 		},
@@ -51,3 +89,16 @@ func envBool(name string) bool {
 	v := strings.ToLower(strings.TrimSpace(os.Getenv(name)))
 	return v == "1" || v == "true" || v == "yes" || v == "on"
 }
+
+func envInt(name string, fallback int) int {
+	v := strings.TrimSpace(os.Getenv(name))
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("ignoring %s=%q: not a number", name, v)
+		return fallback
+	}
+	return n
+}
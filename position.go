@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// overlayOffsetSaveDelay is how long a drag must pause before the offset is written
+const overlayOffsetSaveDelay = time.Second
+
+// OverlayOffset moves the docked overlay away from its computed position, e.g.
+// after the user drags it. It is kept across restarts.
+type OverlayOffset struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// overlayOffsetPath returns where the manual offset is persisted
+func overlayOffsetPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "rez", "overlay-offset.json"), nil
+}
+
+// loadOverlayOffset reads the saved offset. A missing file is a zero offset.
+func loadOverlayOffset() (OverlayOffset, error) {
+	var offset OverlayOffset
+	path, err := overlayOffsetPath()
+	if err != nil {
+		return offset, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return offset, nil
+	}
+	if err != nil {
+		return offset, err
+	}
+	if err := json.Unmarshal(data, &offset); err != nil {
+		return OverlayOffset{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return offset, nil
+}
+
+// saveOverlayOffset writes the offset atomically so a crash mid-write can't
+// leave a truncated file behind
+func saveOverlayOffset(offset OverlayOffset) error {
+	path, err := overlayOffsetPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(offset)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// debouncedSaver runs save once trigger hasn't been called for delay, turning
+// a burst of changes such as a drag into a single write
+type debouncedSaver struct {
+	mu     sync.Mutex
+	saveMu sync.Mutex // serializes save, so flush waits for one already running
+	delay  time.Duration
+	save   func()
+	timer  *time.Timer // pending save; nil when idle, guarded by mu
+	dirty  bool        // a change hasn't been handed to save yet, guarded by mu
+}
+
+func newDebouncedSaver(delay time.Duration, save func()) *debouncedSaver {
+	return &debouncedSaver{delay: delay, save: save}
+}
+
+// trigger schedules a save, pushing back any save already pending
+func (d *debouncedSaver) trigger() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dirty = true
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(d.delay, func() {
+		d.mu.Lock()
+		current := d.timer == timer
+		if current {
+			d.timer = nil
+		}
+		d.mu.Unlock()
+		if current {
+			d.saveIfDirty()
+		}
+	})
+	d.timer = timer
+}
+
+// flush saves any unsaved change now, e.g. on shutdown. It also covers a
+// change made while a timed save was already running, which that save may
+// have missed.
+func (d *debouncedSaver) flush() {
+	d.mu.Lock()
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	d.mu.Unlock()
+	d.saveIfDirty()
+}
+
+// saveIfDirty runs save if anything changed since the last one started
+func (d *debouncedSaver) saveIfDirty() {
+	d.saveMu.Lock()
+	defer d.saveMu.Unlock()
+	d.mu.Lock()
+	dirty := d.dirty
+	d.dirty = false
+	d.mu.Unlock()
+	if dirty {
+		d.save()
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// A change made while a save is running must still be on disk once flush
+// returns, and must not be overwritten by the older save finishing late
+func TestDebouncedSaverFlushAfterTriggerDuringSave(t *testing.T) {
+	var mu sync.Mutex
+	value, saved := 0, []int{}
+	started, release, firstDone := make(chan struct{}), make(chan struct{}), make(chan struct{})
+
+	first := true
+	saver := newDebouncedSaver(time.Millisecond, func() {
+		mu.Lock()
+		v, isFirst := value, first
+		first = false
+		mu.Unlock()
+		if isFirst {
+			// Hold the first save open while the value changes again
+			close(started)
+			<-release
+		}
+		mu.Lock()
+		saved = append(saved, v)
+		mu.Unlock()
+		if isFirst {
+			close(firstDone)
+		}
+	})
+
+	mu.Lock()
+	value = 1
+	mu.Unlock()
+	saver.trigger()
+	<-started
+
+	mu.Lock()
+	value = 2
+	mu.Unlock()
+	saver.trigger()
+	// Let the second timer fire while the first save is still running
+	time.Sleep(20 * time.Millisecond)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	saver.flush()
+	<-firstDone
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(saved) == 0 || saved[len(saved)-1] != 2 {
+		t.Fatalf("saved %v, want the last save to be 2", saved)
+	}
+}
+
+func TestDebouncedSaverFlushIdle(t *testing.T) {
+	calls := 0
+	saver := newDebouncedSaver(time.Hour, func() { calls++ })
+	saver.flush()
+	if calls != 0 {
+		t.Fatalf("flush with nothing pending saved %d times", calls)
+	}
+	saver.trigger()
+	saver.flush()
+	saver.flush()
+	if calls != 1 {
+		t.Fatalf("saved %d times, want 1", calls)
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"rez/internal/mockreplay"
+)
+
+// recorderQueueSize is how many events can wait for the recorder's writer
+// before record blocks
+const recorderQueueSize = 256
+
+// recordedEvent is one payload waiting for the recorder's writer
+type recordedEvent struct {
+	at    time.Time
+	raw   json.RawMessage
+	ended bool
+}
+
+// captureRecorder tees live champ-select events into capture files in the same
+// format the capture binary writes, one file per champ select. Events are
+// handed to a background writer that appends each one to a journal next to
+// the capture (path + ".events", one CapturedEvent per line); the capture
+// itself is written in one go when champ select ends or the recorder is
+// closed, and the journal is removed. A crash leaves the journal behind.
+type captureRecorder struct {
+	dir       string
+	events    chan recordedEvent
+	quit      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// owned by run
+	path      string
+	startedAt time.Time
+	session   *mockreplay.CaptureSession
+	journal   *os.File
+	journalW  *bufio.Writer
+}
+
+func newCaptureRecorder(dir string) *captureRecorder {
+	r := &captureRecorder{
+		dir:    dir,
+		events: make(chan recordedEvent, recorderQueueSize),
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// record queues a raw [type, name, event] websocket payload for the current
+// capture, starting a new file on the first event and finishing it on Delete.
+// Events recorded after close are dropped.
+func (r *captureRecorder) record(payload []any) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	select {
+	case r.events <- recordedEvent{at: time.Now(), raw: raw, ended: isDeletePayload(payload)}:
+	case <-r.quit:
+	}
+}
+
+// close writes out the capture in progress, if any, and stops the writer
+func (r *captureRecorder) close() {
+	r.closeOnce.Do(func() { close(r.quit) })
+	<-r.done
+}
+
+// run is the background writer; it owns the session and the journal
+func (r *captureRecorder) run() {
+	defer close(r.done)
+	for {
+		select {
+		case ev := <-r.events:
+			r.append(ev)
+			if len(r.events) == 0 {
+				r.flushJournal()
+			}
+		case <-r.quit:
+			for {
+				select {
+				case ev := <-r.events:
+					r.append(ev)
+				default:
+					r.finish()
+					return
+				}
+			}
+		}
+	}
+}
+
+// append adds ev to the session and its journal
+func (r *captureRecorder) append(ev recordedEvent) {
+	if r.session == nil {
+		r.start(ev.at)
+	}
+
+	offset := ev.at.Sub(r.startedAt).Milliseconds()
+	captured := mockreplay.CapturedEvent{
+		Timestamp: ev.at.Format(time.RFC3339Nano),
+		OffsetMs:  &offset,
+		RawData:   ev.raw,
+	}
+	r.session.Events = append(r.session.Events, captured)
+	r.session.EventCount = len(r.session.Events)
+
+	if r.journalW != nil {
+		if line, err := json.Marshal(captured); err == nil {
+			r.journalW.Write(append(line, '\n'))
+		}
+	}
+
+	if ev.ended {
+		r.session.EndTime = ev.at.Format(time.RFC3339)
+		r.finish()
+	}
+}
+
+// start begins a new capture file at now
+func (r *captureRecorder) start(now time.Time) {
+	r.startedAt = now
+	r.path = filepath.Join(r.dir, fmt.Sprintf("champ-select-capture_%s.json", now.Format("20060102_150405")))
+	r.session = &mockreplay.CaptureSession{StartTime: now.Format(time.RFC3339)}
+	log.Println("Recording champ select to", r.path)
+
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		log.Printf("failed to create recording directory: %v", err)
+		return
+	}
+	journal, err := os.Create(r.path + ".events")
+	if err != nil {
+		log.Printf("failed to open recording journal: %v", err)
+		return
+	}
+	r.journal = journal
+	r.journalW = bufio.NewWriter(journal)
+}
+
+func (r *captureRecorder) flushJournal() {
+	if r.journalW == nil {
+		return
+	}
+	if err := r.journalW.Flush(); err != nil {
+		log.Printf("failed to write recording journal: %v", err)
+	}
+}
+
+// finish writes the whole capture atomically and drops its journal
+func (r *captureRecorder) finish() {
+	if r.session == nil {
+		return
+	}
+
+	err := mockreplay.WriteCapture(r.path, r.session)
+	if err != nil {
+		log.Printf("failed to write recording: %v", err)
+	} else {
+		log.Printf("Recorded %d events to %s", r.session.EventCount, r.path)
+	}
+
+	if r.journal != nil {
+		r.flushJournal()
+		r.journal.Close()
+		if err == nil {
+			os.Remove(r.journal.Name())
+		}
+	}
+	r.session, r.journal, r.journalW = nil, nil, nil
+}
+
+func isDeletePayload(payload []any) bool {
+	if len(payload) < 3 {
+		return false
+	}
+	event, ok := payload[2].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	eventType, _ := event["eventType"].(string)
+	return strings.EqualFold(eventType, "Delete")
+}